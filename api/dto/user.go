@@ -0,0 +1,223 @@
+// Package dto 定义用户 API 的请求与响应结构
+// 请求结构带 validate 校验标签并实现 validation.Normalizer，处理器与用户服务客户端共用
+package dto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// ==================== 请求 ====================
+
+// RegisterRequest 用户注册请求
+type RegisterRequest struct {
+	Username         string `json:"username" validate:"required,min=3,max=32"`
+	Email            string `json:"email,omitempty" validate:"required_without=Phone,omitempty,email,max=254"`
+	Phone            string `json:"phone,omitempty" validate:"omitempty,e164"`
+	Password         string `json:"password" validate:"required,min=8,max=72"`
+	Nickname         string `json:"nickname,omitempty" validate:"omitempty,max=64"`
+	VerificationCode string `json:"verification_code,omitempty" validate:"omitempty,numeric,len=6"`
+}
+
+// Normalize 规范化请求数据
+func (r *RegisterRequest) Normalize() {
+	r.Username = strings.TrimSpace(r.Username)
+	r.Email = normalizeEmail(r.Email)
+	r.Phone = strings.TrimSpace(r.Phone)
+	r.Nickname = strings.TrimSpace(r.Nickname)
+	r.VerificationCode = strings.TrimSpace(r.VerificationCode)
+}
+
+// LoginRequest 用户登录请求，用户名、邮箱、手机号任选其一
+type LoginRequest struct {
+	Username string `json:"username,omitempty" validate:"required_without_all=Email Phone,omitempty,max=32"`
+	Email    string `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	Phone    string `json:"phone,omitempty" validate:"omitempty,e164"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+// Normalize 规范化请求数据
+func (r *LoginRequest) Normalize() {
+	r.Username = strings.TrimSpace(r.Username)
+	r.Email = normalizeEmail(r.Email)
+	r.Phone = strings.TrimSpace(r.Phone)
+}
+
+// LogoutRequest 用户登出请求（请求体可选）
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty" validate:"omitempty,max=4096"`
+}
+
+// Normalize 规范化请求数据
+func (r *LogoutRequest) Normalize() {
+	r.RefreshToken = strings.TrimSpace(r.RefreshToken)
+}
+
+// RefreshTokenRequest 刷新 Token 请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required,max=4096"`
+}
+
+// Normalize 规范化请求数据
+func (r *RefreshTokenRequest) Normalize() {
+	r.RefreshToken = strings.TrimSpace(r.RefreshToken)
+}
+
+// UpdateUserRequest 更新用户信息请求，只更新非空字段
+type UpdateUserRequest struct {
+	Nickname *string `json:"nickname,omitempty" validate:"omitempty,max=64"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	Phone    *string `json:"phone,omitempty" validate:"omitempty,e164"`
+	Avatar   *string `json:"avatar,omitempty" validate:"omitempty,http_url,max=512"`
+}
+
+// Normalize 规范化请求数据
+func (r *UpdateUserRequest) Normalize() {
+	trimPtr(r.Nickname)
+	trimPtr(r.Phone)
+	trimPtr(r.Avatar)
+	if r.Email != nil {
+		*r.Email = normalizeEmail(*r.Email)
+	}
+}
+
+// Validate 至少需要更新一个字段
+func (r *UpdateUserRequest) Validate(context.Context) error {
+	if r.Nickname == nil && r.Email == nil && r.Phone == nil && r.Avatar == nil {
+		return apperrors.ErrValidation("至少需要更新一个字段")
+	}
+	return nil
+}
+
+// ResetPasswordRequest 重置密码请求，邮箱、手机号任选其一
+type ResetPasswordRequest struct {
+	Email            string `json:"email,omitempty" validate:"required_without=Phone,omitempty,email,max=254"`
+	Phone            string `json:"phone,omitempty" validate:"omitempty,e164"`
+	VerificationCode string `json:"verification_code" validate:"required,numeric,len=6"`
+	NewPassword      string `json:"new_password" validate:"required,min=8,max=72"`
+}
+
+// Normalize 规范化请求数据
+func (r *ResetPasswordRequest) Normalize() {
+	r.Email = normalizeEmail(r.Email)
+	r.Phone = strings.TrimSpace(r.Phone)
+	r.VerificationCode = strings.TrimSpace(r.VerificationCode)
+}
+
+// 验证码用途
+const (
+	PurposeRegister      = "register"       // 注册
+	PurposeLogin         = "login"          // 登录
+	PurposeResetPassword = "reset_password" // 重置密码
+)
+
+// SendVerificationCodeRequest 发送验证码请求，邮箱、手机号任选其一
+type SendVerificationCodeRequest struct {
+	Email   string `json:"email,omitempty" validate:"required_without=Phone,omitempty,email,max=254"`
+	Phone   string `json:"phone,omitempty" validate:"omitempty,e164"`
+	Purpose string `json:"purpose" validate:"required,oneof=register login reset_password"`
+}
+
+// Normalize 规范化请求数据
+func (r *SendVerificationCodeRequest) Normalize() {
+	r.Email = normalizeEmail(r.Email)
+	r.Phone = strings.TrimSpace(r.Phone)
+	r.Purpose = strings.ToLower(strings.TrimSpace(r.Purpose))
+}
+
+// VerifyCodeRequest 验证验证码请求
+type VerifyCodeRequest struct {
+	Email   string `json:"email,omitempty" validate:"required_without=Phone,omitempty,email,max=254"`
+	Phone   string `json:"phone,omitempty" validate:"omitempty,e164"`
+	Purpose string `json:"purpose" validate:"required,oneof=register login reset_password"`
+	Code    string `json:"code" validate:"required,numeric,len=6"`
+}
+
+// Normalize 规范化请求数据
+func (r *VerifyCodeRequest) Normalize() {
+	r.Email = normalizeEmail(r.Email)
+	r.Phone = strings.TrimSpace(r.Phone)
+	r.Purpose = strings.ToLower(strings.TrimSpace(r.Purpose))
+	r.Code = strings.TrimSpace(r.Code)
+}
+
+// ==================== 响应 ====================
+
+// ID 用户 ID，兼容用户服务返回的数字与字符串两种格式
+type ID string
+
+// UnmarshalJSON 解析数字或字符串格式的 ID
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = ID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = ID(n.String())
+	return nil
+}
+
+// UserResponse 用户信息
+type UserResponse struct {
+	ID        ID         `json:"id"`
+	Username  string     `json:"username"`
+	Email     string     `json:"email,omitempty"`
+	Phone     string     `json:"phone,omitempty"`
+	Nickname  string     `json:"nickname,omitempty"`
+	Avatar    string     `json:"avatar,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// TokenResponse 访问令牌
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"` // 有效期（秒）
+}
+
+// LoginResponse 登录结果
+type LoginResponse struct {
+	TokenResponse
+	User *UserResponse `json:"user,omitempty"`
+}
+
+// SendVerificationCodeResponse 发送验证码结果
+type SendVerificationCodeResponse struct {
+	ExpiresIn int64 `json:"expires_in,omitempty"` // 验证码有效期（秒）
+}
+
+// VerifyCodeResponse 验证码校验结果
+type VerifyCodeResponse struct {
+	Valid bool `json:"valid"`
+}
+
+// normalizeEmail 邮箱去除首尾空格并转小写
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// trimPtr 去除字符串指针指向值的首尾空格
+func trimPtr(s *string) {
+	if s != nil {
+		*s = strings.TrimSpace(*s)
+	}
+}
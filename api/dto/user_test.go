@@ -0,0 +1,136 @@
+package dto
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	apperrors "soliton-client/share/errors"
+	"soliton-client/share/validation"
+)
+
+// check 规范化并校验请求，返回校验失败的字段
+func check(t *testing.T, req validation.Normalizer) []string {
+	t.Helper()
+	req.Normalize()
+	err := validation.Struct(context.Background(), req)
+	if err == nil {
+		return nil
+	}
+	appErr, ok := apperrors.AsAppError(err)
+	if !ok || appErr.Code != apperrors.Validation {
+		t.Fatalf("err = %v, want validation error", err)
+	}
+	fields := make([]string, len(appErr.Violations))
+	for i, v := range appErr.Violations {
+		fields[i] = v.Field
+	}
+	if len(fields) == 0 {
+		fields = []string{""} // 自定义校验失败，没有字段
+	}
+	return fields
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestRequestValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        validation.Normalizer
+		wantFields []string // 期望校验失败的字段，为空表示校验通过
+	}{
+		{name: "register with email", req: &RegisterRequest{Username: " alice ", Email: "Alice@Example.com", Password: "Passw0rd!"}},
+		{name: "register with phone", req: &RegisterRequest{Username: "alice", Phone: "+8613800138000", Password: "Passw0rd!"}},
+		{name: "register without contact", req: &RegisterRequest{Username: "alice", Password: "Passw0rd!"}, wantFields: []string{"email"}},
+		{
+			name:       "register invalid fields",
+			req:        &RegisterRequest{Username: " al ", Email: "bad", Password: "short", VerificationCode: "12ab56"},
+			wantFields: []string{"username", "email", "password", "verification_code"},
+		},
+		{name: "login by username", req: &LoginRequest{Username: "alice", Password: "x"}},
+		{name: "login by phone", req: &LoginRequest{Phone: "+8613800138000", Password: "x"}},
+		{name: "login without identity", req: &LoginRequest{Password: "x"}, wantFields: []string{"username"}},
+		{name: "logout empty", req: &LogoutRequest{}},
+		{name: "refresh blank token", req: &RefreshTokenRequest{RefreshToken: "  "}, wantFields: []string{"refresh_token"}},
+		{name: "update one field", req: &UpdateUserRequest{Nickname: strPtr("Bob")}},
+		{name: "update nothing", req: &UpdateUserRequest{}, wantFields: []string{""}},
+		{name: "update invalid avatar", req: &UpdateUserRequest{Avatar: strPtr("ftp://img")}, wantFields: []string{"avatar"}},
+		{name: "reset password", req: &ResetPasswordRequest{Email: "a@example.com", VerificationCode: " 123456 ", NewPassword: "Passw0rd!"}},
+		{
+			name:       "reset password bad code",
+			req:        &ResetPasswordRequest{Phone: "+8613800138000", VerificationCode: "12345", NewPassword: "Passw0rd!"},
+			wantFields: []string{"verification_code"},
+		},
+		{name: "send code purpose case", req: &SendVerificationCodeRequest{Email: "a@example.com", Purpose: " Register "}},
+		{name: "send code unknown purpose", req: &SendVerificationCodeRequest{Email: "a@example.com", Purpose: "admin"}, wantFields: []string{"purpose"}},
+		{name: "verify code", req: &VerifyCodeRequest{Phone: "+8613800138000", Purpose: PurposeLogin, Code: "654321"}},
+		{name: "verify code missing", req: &VerifyCodeRequest{Email: "a@example.com", Purpose: PurposeLogin}, wantFields: []string{"code"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := check(t, tt.req); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("invalid fields = %q, want %q", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	register := &RegisterRequest{Username: " alice ", Email: " Alice@Example.COM ", Phone: " +8613800138000 ", Nickname: " A "}
+	register.Normalize()
+	if register.Username != "alice" || register.Email != "alice@example.com" || register.Phone != "+8613800138000" || register.Nickname != "A" {
+		t.Errorf("register = %+v", register)
+	}
+
+	update := &UpdateUserRequest{Email: strPtr(" Bob@Example.com "), Nickname: strPtr(" Bob "), Phone: nil}
+	update.Normalize()
+	if *update.Email != "bob@example.com" || *update.Nickname != "Bob" || update.Phone != nil {
+		t.Errorf("update = %+v", update)
+	}
+}
+
+func TestIDUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    ID
+		wantErr bool
+	}{
+		{name: "string", data: `"u-1"`, want: "u-1"},
+		{name: "number", data: `42`, want: "42"},
+		{name: "large number", data: `9007199254740993`, want: "9007199254740993"},
+		{name: "null", data: `null`, want: ""},
+		{name: "object", data: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var user UserResponse
+			err := json.Unmarshal([]byte(`{"id":`+tt.data+`}`), &user)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if user.ID != tt.want {
+				t.Errorf("id = %q, want %q", user.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginResponseJSON(t *testing.T) {
+	var resp LoginResponse
+	body := `{"access_token":"at","refresh_token":"rt","expires_in":3600,"user":{"id":7,"username":"alice"}}`
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.AccessToken != "at" || resp.ExpiresIn != 3600 || resp.User == nil || resp.User.ID != "7" {
+		t.Fatalf("resp = %+v", resp)
+	}
+	// 令牌字段内嵌展开，与用户服务的格式一致
+	out, _ := json.Marshal(resp)
+	var flat map[string]interface{}
+	_ = json.Unmarshal(out, &flat)
+	if flat["access_token"] != "at" || flat["user"] == nil {
+		t.Errorf("json = %s", out)
+	}
+}
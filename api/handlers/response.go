@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+
+	apperrors "soliton-client/share/errors"
+	"soliton-client/share/types"
+)
+
+// legacyResponse 是否使用旧版响应格式
+var legacyResponse atomic.Bool
+
+// SetLegacyResponse 设置是否使用旧版响应格式
+// 旧版格式成功时按接口返回 {"message": ..., "user": ...} 或原始数据，失败时返回 {"error": ...}；
+// 仅用于客户端迁移期间，默认使用统一响应结构 types.Response
+func SetLegacyResponse(enabled bool) {
+	legacyResponse.Store(enabled)
+}
+
+// legacy 旧版成功响应的构造函数，参数为响应数据
+type legacy func(data interface{}) interface{}
+
+// legacyData 旧版格式直接返回数据
+func legacyData(data interface{}) interface{} {
+	return data
+}
+
+// legacyMessage 旧版格式返回消息
+func legacyMessage(message string) legacy {
+	return func(interface{}) interface{} {
+		return map[string]interface{}{"message": message}
+	}
+}
+
+// legacyUser 旧版格式返回消息与用户信息
+func legacyUser(message string) legacy {
+	return func(data interface{}) interface{} {
+		return map[string]interface{}{"message": message, "user": data}
+	}
+}
+
+// writeSuccess 写出成功响应
+func writeSuccess[T any](ctx context.Context, c *app.RequestContext, message string, data T, old legacy) {
+	if legacyResponse.Load() {
+		c.JSON(consts.StatusOK, old(data))
+		return
+	}
+	c.JSON(consts.StatusOK, types.SuccessWithMessage(message, data).WithTrace(ctx))
+}
+
+// writeError 写出错误响应
+func writeError(ctx context.Context, c *app.RequestContext, err error) {
+	if legacyResponse.Load() {
+		appErr, ok := apperrors.AsAppError(err)
+		if !ok {
+			appErr = apperrors.FromCode(apperrors.InternalError)
+		}
+		message := appErr.Message
+		if message == "" {
+			message = apperrors.FromCode(appErr.Code).Message
+		}
+		c.JSON(appErr.HTTPStatus(), map[string]interface{}{"error": message})
+		return
+	}
+	apperrors.HandleError(ctx, c, err)
+}
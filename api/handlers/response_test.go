@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// useLegacyResponse 在测试期间设置响应格式，结束后恢复默认格式
+func useLegacyResponse(t *testing.T, enabled bool) {
+	t.Helper()
+	SetLegacyResponse(enabled)
+	t.Cleanup(func() { SetLegacyResponse(false) })
+}
+
+func TestLegacyResponse(t *testing.T) {
+	user := map[string]interface{}{"id": "1", "username": "alice", "email": "alice@example.com"}
+	tests := []struct {
+		name       string
+		legacy     bool
+		setup      func(f *fakeUserService)
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		want       map[string]interface{} // 期望的响应体（统一格式只比较 code、message 与 data）
+	}{
+		{
+			name:       "legacy raw data",
+			legacy:     true,
+			setup:      func(f *fakeUserService) { f.reply("GET /api/v1/users/1", user) },
+			method:     "GET",
+			path:       "/api/v1/users/1",
+			wantStatus: http.StatusOK,
+			want:       user,
+		},
+		{
+			name:       "legacy message",
+			legacy:     true,
+			setup:      func(f *fakeUserService) { f.reply("POST /api/v1/auth/logout", nil) },
+			method:     "POST",
+			path:       "/api/v1/users/logout",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"message": "登出成功"},
+		},
+		{
+			name:   "legacy error",
+			legacy: true,
+			setup: func(f *fakeUserService) {
+				f.replyError("GET /api/v1/users/9", http.StatusNotFound, 10004, "用户不存在")
+			},
+			method:     "GET",
+			path:       "/api/v1/users/9",
+			wantStatus: http.StatusNotFound,
+			want:       map[string]interface{}{"error": "用户不存在"},
+		},
+		{
+			name:       "legacy validation error",
+			legacy:     true,
+			setup:      func(*fakeUserService) {},
+			method:     "POST",
+			path:       "/api/v1/users/password/reset",
+			body:       `{}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "unified success",
+			setup:      func(f *fakeUserService) { f.reply("GET /api/v1/users/1", user) },
+			method:     "GET",
+			path:       "/api/v1/users/1",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"code": float64(0), "message": "success", "data": user},
+		},
+		{
+			name:       "unified message without data",
+			setup:      func(f *fakeUserService) { f.reply("POST /api/v1/auth/logout", nil) },
+			method:     "POST",
+			path:       "/api/v1/users/logout",
+			wantStatus: http.StatusOK,
+			want:       map[string]interface{}{"code": float64(0), "message": "登出成功"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useLegacyResponse(t, tt.legacy)
+			upstream, client := newFakeUserService(t)
+			tt.setup(upstream)
+
+			status, body := perform(t, newTestRouter(client), tt.method, tt.path, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %v", status, tt.wantStatus, body)
+			}
+			if tt.want == nil {
+				// 旧版错误只返回 error 字段
+				if len(body) != 1 || body["error"] == "" {
+					t.Errorf("body = %v, want only error", body)
+				}
+				return
+			}
+			got := body
+			if !tt.legacy {
+				got = map[string]interface{}{"code": body["code"], "message": body["message"]}
+				if data, ok := body["data"]; ok {
+					got["data"] = data
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("body = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+
+	apperrors "soliton-client/share/errors"
+	"soliton-client/share/types"
+)
+
+// 用户模块错误码 11000-11999
+const (
+	UserServiceError         = 11006 // 用户服务内部错误
+	UserServiceTimeout       = 11008 // 用户服务响应超时
+	UserServiceUnavailable   = 11010 // 用户服务无法连接
+	UserServiceBadResponse   = 11011 // 用户服务响应格式错误
+	UserPatchUnsupported     = 11012 // 补丁格式不支持
+	UserPreconditionRequired = 11013 // 缺少 If-Match 条件
+	UserPreconditionFailed   = 11014 // 用户信息已被修改
+)
+
+func init() {
+	apperrors.MustRegister(apperrors.ModuleUser,
+		apperrors.Definition{Code: UserServiceError, Status: http.StatusBadGateway, Key: "user.service_error",
+			Messages: map[string]string{apperrors.LocaleZhCN: "用户服务处理失败", apperrors.LocaleEnUS: "User service failed to process the request"}},
+		apperrors.Definition{Code: UserServiceTimeout, Status: http.StatusGatewayTimeout, Key: "user.service_timeout",
+			Messages: map[string]string{apperrors.LocaleZhCN: "用户服务响应超时", apperrors.LocaleEnUS: "User service timed out"}},
+		apperrors.Definition{Code: UserServiceUnavailable, Status: http.StatusServiceUnavailable, Key: "user.service_unavailable",
+			Messages: map[string]string{apperrors.LocaleZhCN: "用户服务暂不可用", apperrors.LocaleEnUS: "User service unavailable"}},
+		apperrors.Definition{Code: UserServiceBadResponse, Status: http.StatusBadGateway, Key: "user.service_bad_response",
+			Messages: map[string]string{apperrors.LocaleZhCN: "用户服务响应异常", apperrors.LocaleEnUS: "User service returned an invalid response"}},
+		apperrors.Definition{Code: UserPatchUnsupported, Status: http.StatusUnsupportedMediaType, Key: "user.patch_unsupported",
+			Messages: map[string]string{apperrors.LocaleZhCN: "请求体必须为 application/merge-patch+json", apperrors.LocaleEnUS: "Request body must be application/merge-patch+json"}},
+		apperrors.Definition{Code: UserPreconditionRequired, Status: http.StatusPreconditionRequired, Key: "user.precondition_required",
+			Messages: map[string]string{apperrors.LocaleZhCN: "缺少 If-Match 请求头", apperrors.LocaleEnUS: "If-Match header is required"}},
+		apperrors.Definition{Code: UserPreconditionFailed, Status: http.StatusPreconditionFailed, Key: "user.precondition_failed",
+			Messages: map[string]string{apperrors.LocaleZhCN: "用户信息已被修改，请刷新后重试", apperrors.LocaleEnUS: "User was modified, please reload and retry"}},
+	)
+}
+
+var (
+	upstreamMu sync.RWMutex
+	// upstreamCodes 用户服务错误码到本服务错误码的映射
+	// 用户服务与本服务共用通用错误码，其内部错误转换为用户模块错误码；
+	// 用户服务自身的业务错误码按 HTTP 状态码转换，需要更精确的映射时通过 MapUpstreamCode 添加
+	upstreamCodes = map[int]int{
+		apperrors.BadRequest:         apperrors.BadRequest,
+		apperrors.Unauthorized:       apperrors.Unauthorized,
+		apperrors.Forbidden:          apperrors.Forbidden,
+		apperrors.NotFound:           apperrors.NotFound,
+		apperrors.Conflict:           apperrors.Conflict,
+		apperrors.Validation:         apperrors.Validation,
+		apperrors.TooManyRequests:    apperrors.TooManyRequests,
+		apperrors.InternalError:      UserServiceError,
+		apperrors.Timeout:            UserServiceTimeout,
+		apperrors.ServiceUnavailable: UserServiceUnavailable,
+	}
+	// upstreamStatuses 映射表中没有的错误码按用户服务的 HTTP 状态码转换
+	upstreamStatuses = map[int]int{
+		http.StatusBadRequest:          apperrors.BadRequest,
+		http.StatusUnauthorized:        apperrors.Unauthorized,
+		http.StatusForbidden:           apperrors.Forbidden,
+		http.StatusNotFound:            apperrors.NotFound,
+		http.StatusConflict:            apperrors.Conflict,
+		http.StatusUnprocessableEntity: apperrors.Validation,
+		http.StatusPreconditionFailed:  UserPreconditionFailed,
+		http.StatusTooManyRequests:     apperrors.TooManyRequests,
+		http.StatusGatewayTimeout:      UserServiceTimeout,
+		http.StatusServiceUnavailable:  UserServiceUnavailable,
+	}
+)
+
+// MapUpstreamCode 添加或覆盖用户服务错误码的映射，用于用户服务新增的业务错误码
+func MapUpstreamCode(upstream, local int) {
+	upstreamMu.Lock()
+	defer upstreamMu.Unlock()
+	upstreamCodes[upstream] = local
+}
+
+// localCode 用户服务错误码对应的本服务错误码
+// 先查映射表，再按 HTTP 状态码转换，都不匹配时视为用户服务内部错误
+func localCode(upstream, statusCode int) int {
+	upstreamMu.RLock()
+	defer upstreamMu.RUnlock()
+	if code, ok := upstreamCodes[upstream]; ok {
+		return code
+	}
+	if code, ok := upstreamStatuses[statusCode]; ok {
+		return code
+	}
+	return UserServiceError
+}
+
+// upstreamError 将用户服务返回的业务错误转换为 AppError
+// 客户端错误（4xx 类）保留用户服务的消息与校验详情；服务端错误只返回默认消息，原始信息记录日志
+func upstreamError(ctx context.Context, resp *types.Response[json.RawMessage], statusCode int) error {
+	code := localCode(resp.Code, statusCode)
+	appErr := apperrors.FromCode(code).
+		WithMetadata("upstream_code", resp.Code)
+	if appErr.HTTPStatus() >= http.StatusInternalServerError {
+		hlog.CtxErrorf(ctx, "user service error: status=%d code=%d message=%s", statusCode, resp.Code, resp.Message)
+		return appErr
+	}
+
+	if resp.Message != "" {
+		appErr.Message = resp.Message
+	}
+	if details, ok := resp.Details.(map[string]interface{}); ok {
+		if raw, err := json.Marshal(details["violations"]); err == nil {
+			var violations []apperrors.FieldViolation
+			if json.Unmarshal(raw, &violations) == nil {
+				appErr.WithViolations(violations...)
+			}
+		}
+	}
+	return appErr
+}
+
+// transportError 对调用用户服务的传输错误分类：超时、响应格式错误，其余（连接被拒绝、DNS 解析失败等）视为无法连接
+// 原始错误（可能包含内部地址）只记录日志，不返回给客户端
+func transportError(ctx context.Context, method, path string, err error) error {
+	code := UserServiceUnavailable
+	var (
+		netErr    net.Error
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		code = UserServiceTimeout
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		code = UserServiceBadResponse
+	}
+	hlog.CtxErrorf(ctx, "user service %s %s failed: %v", method, path, err)
+	return apperrors.Wrap(code, "", err)
+}
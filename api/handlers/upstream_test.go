@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "soliton-client/share/errors"
+	"soliton-client/share/types"
+)
+
+func TestLocalCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream int
+		status   int
+		want     int
+	}{
+		{name: "common code", upstream: apperrors.Forbidden, status: http.StatusOK, want: apperrors.Forbidden},
+		{name: "common code wins over status", upstream: apperrors.NotFound, status: http.StatusBadRequest, want: apperrors.NotFound},
+		{name: "business code by status", upstream: 20101, status: http.StatusUnauthorized, want: apperrors.Unauthorized},
+		{name: "precondition failed", upstream: 20102, status: http.StatusPreconditionFailed, want: UserPreconditionFailed},
+		{name: "internal error", upstream: apperrors.InternalError, status: http.StatusInternalServerError, want: UserServiceError},
+		{name: "unknown code by status", upstream: 19999, status: http.StatusConflict, want: apperrors.Conflict},
+		{name: "unknown code and status", upstream: 19999, status: http.StatusTeapot, want: UserServiceError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := localCode(tt.upstream, tt.status); got != tt.want {
+				t.Errorf("localCode(%d, %d) = %d, want %d", tt.upstream, tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapUpstreamCode(t *testing.T) {
+	const code = 20199
+	MapUpstreamCode(code, apperrors.TooManyRequests)
+	t.Cleanup(func() {
+		upstreamMu.Lock()
+		delete(upstreamCodes, code)
+		upstreamMu.Unlock()
+	})
+	if got := localCode(code, http.StatusBadRequest); got != apperrors.TooManyRequests {
+		t.Fatalf("localCode = %d, want %d", got, apperrors.TooManyRequests)
+	}
+}
+
+func TestUpstreamError(t *testing.T) {
+	tests := []struct {
+		name        string
+		resp        *types.Response[json.RawMessage]
+		status      int
+		wantCode    int
+		wantMessage string // 为空表示期望使用注册的默认消息
+		wantFields  int
+	}{
+		{
+			name:        "client error keeps message",
+			resp:        &types.Response[json.RawMessage]{Code: 20100, Message: "用户名已被占用"},
+			status:      http.StatusConflict,
+			wantCode:    apperrors.Conflict,
+			wantMessage: "用户名已被占用",
+		},
+		{
+			name: "violations kept",
+			resp: &types.Response[json.RawMessage]{Code: apperrors.Validation, Message: "参数错误", Details: map[string]interface{}{
+				"violations": []interface{}{map[string]interface{}{"field": "email", "rule": "email", "message": "邮箱格式不正确"}},
+			}},
+			status:      http.StatusUnprocessableEntity,
+			wantCode:    apperrors.Validation,
+			wantMessage: "参数错误",
+			wantFields:  1,
+		},
+		{
+			name:     "server error hides message",
+			resp:     &types.Response[json.RawMessage]{Code: apperrors.InternalError, Message: "pq: connection to 10.0.0.5 refused"},
+			status:   http.StatusInternalServerError,
+			wantCode: UserServiceError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr, ok := apperrors.AsAppError(upstreamError(context.Background(), tt.resp, tt.status))
+			if !ok || appErr.Code != tt.wantCode {
+				t.Fatalf("err = %v, want code %d", appErr, tt.wantCode)
+			}
+			wantMessage := tt.wantMessage
+			if wantMessage == "" {
+				wantMessage = apperrors.FromCode(tt.wantCode).Message
+			}
+			if appErr.Message != wantMessage {
+				t.Errorf("message = %q, want %q", appErr.Message, wantMessage)
+			}
+			if len(appErr.Violations) != tt.wantFields {
+				t.Errorf("violations = %+v, want %d", appErr.Violations, tt.wantFields)
+			}
+			if appErr.Metadata["upstream_code"] != tt.resp.Code {
+				t.Errorf("metadata = %v, want upstream code %d", appErr.Metadata, tt.resp.Code)
+			}
+		})
+	}
+}
+
+// timeoutError 超时的网络错误
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestTransportError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "deadline", err: fmt.Errorf("do: %w", context.DeadlineExceeded), want: UserServiceTimeout},
+		{name: "net timeout", err: &net.OpError{Op: "read", Err: timeoutError{}}, want: UserServiceTimeout},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: UserServiceUnavailable},
+		{name: "bad json", err: json.Unmarshal([]byte("<html>"), &struct{}{}), want: UserServiceBadResponse},
+		{name: "wrong json type", err: json.Unmarshal([]byte(`{"id":1}`), &struct{ ID string }{}), want: UserServiceBadResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr, ok := apperrors.AsAppError(transportError(context.Background(), "GET", "/api/v1/users/1", tt.err))
+			if !ok || appErr.Code != tt.want {
+				t.Fatalf("err = %v, want code %d", appErr, tt.want)
+			}
+			if !errors.Is(appErr, tt.err) {
+				t.Errorf("err = %v, want wrapping %v", appErr, tt.err)
+			}
+		})
+	}
+}
+
+// replyRaw 设置原样返回的响应体
+func (f *fakeUserService) replyRaw(route string, status int, body string) {
+	f.routes[route] = func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestUpstreamErrorResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(f *fakeUserService)
+		path        string
+		body        string
+		wantStatus  int
+		wantCode    int
+		wantMessage string // 为空表示不检查
+	}{
+		{
+			name: "business error by status",
+			setup: func(f *fakeUserService) {
+				f.replyRaw("POST /api/v1/users/register", http.StatusConflict,
+					`{"code":20100,"message":"用户名已被占用","trace_id":"up-1"}`)
+			},
+			path:        "/api/v1/users/register",
+			body:        `{"username":"alice","email":"alice@example.com","password":"Passw0rd!"}`,
+			wantStatus:  http.StatusConflict,
+			wantCode:    apperrors.Conflict,
+			wantMessage: "用户名已被占用",
+		},
+		{
+			name: "mapped business code",
+			setup: func(f *fakeUserService) {
+				MapUpstreamCode(20199, apperrors.Unauthorized)
+				f.t.Cleanup(func() {
+					upstreamMu.Lock()
+					delete(upstreamCodes, 20199)
+					upstreamMu.Unlock()
+				})
+				f.replyRaw("POST /api/v1/auth/login", http.StatusBadRequest, `{"code":20199,"message":"用户名或密码错误"}`)
+			},
+			path:        "/api/v1/users/login",
+			body:        `{"username":"alice","password":"wrong-password"}`,
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    apperrors.Unauthorized,
+			wantMessage: "用户名或密码错误",
+		},
+		{
+			name: "validation details",
+			setup: func(f *fakeUserService) {
+				f.replyRaw("POST /api/v1/users/register", http.StatusUnprocessableEntity,
+					`{"code":10007,"message":"参数错误","details":{"violations":[{"field":"username","rule":"unique","message":"已被占用"}]}}`)
+			},
+			path:       "/api/v1/users/register",
+			body:       `{"username":"alice","email":"alice@example.com","password":"Passw0rd!"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   apperrors.Validation,
+		},
+		{
+			name: "upstream internal error",
+			setup: func(f *fakeUserService) {
+				f.replyRaw("POST /api/v1/auth/login", http.StatusInternalServerError,
+					`{"code":10006,"message":"dial tcp 10.0.0.5:5432: connection refused"}`)
+			},
+			path:       "/api/v1/users/login",
+			body:       `{"username":"alice","password":"Passw0rd!"}`,
+			wantStatus: http.StatusBadGateway,
+			wantCode:   UserServiceError,
+		},
+		{
+			name: "upstream html error page",
+			setup: func(f *fakeUserService) {
+				f.replyRaw("POST /api/v1/auth/login", http.StatusBadGateway, `<html>bad gateway</html>`)
+			},
+			path:       "/api/v1/users/login",
+			body:       `{"username":"alice","password":"Passw0rd!"}`,
+			wantStatus: http.StatusBadGateway,
+			wantCode:   UserServiceBadResponse,
+		},
+		{
+			name: "upstream timeout",
+			setup: func(f *fakeUserService) {
+				f.routes["POST /api/v1/auth/login"] = func(w http.ResponseWriter, _ *http.Request) {
+					time.Sleep(100 * time.Millisecond)
+				}
+			},
+			path:       "/api/v1/users/login",
+			body:       `{"username":"alice","password":"Passw0rd!"}`,
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   UserServiceTimeout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream, client := newFakeUserService(t)
+			client.HTTPClient.Timeout = 20 * time.Millisecond
+			tt.setup(upstream)
+
+			status, body := perform(t, newTestRouter(client), "POST", tt.path, tt.body)
+			if status != tt.wantStatus || body["code"] != float64(tt.wantCode) {
+				t.Fatalf("status = %d, body = %v, want %d code %d", status, body, tt.wantStatus, tt.wantCode)
+			}
+			message, _ := body["message"].(string)
+			if tt.wantMessage != "" && message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", message, tt.wantMessage)
+			}
+			// 不向客户端暴露用户服务及其依赖的地址
+			if strings.Contains(message, "127.0.0.1") || strings.Contains(message, "10.0.0.5") {
+				t.Errorf("message leaks internal address: %q", message)
+			}
+		})
+	}
+}
@@ -1,17 +1,17 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
+	"mime"
+	"strconv"
+	"strings"
 
-	"soliton-client/share/types"
+	"soliton-client/api/dto"
+	apperrors "soliton-client/share/errors"
+	"soliton-client/share/patch"
+	"soliton-client/share/validation"
 
 	"github.com/cloudwego/hertz/pkg/app"
-	"github.com/cloudwego/hertz/pkg/protocol/consts"
 	"github.com/cloudwego/hertz/pkg/route"
 )
 
@@ -25,6 +25,7 @@ func RegisterUserRoutes(router *route.RouterGroup, userClient *UserServiceClient
 		users.POST("/refresh", handleRefreshToken(userClient))         // 刷新 Token
 		users.GET("/:id", handleGetUser(userClient))                   // 获取用户信息
 		users.PUT("/:id", handleUpdateUser(userClient))                // 更新用户信息
+		users.PATCH("/:id", handlePatchUser(userClient))               // 按补丁部分更新用户信息
 		users.POST("/password/reset", handleResetPassword(userClient)) // 重置密码
 	}
 
@@ -36,379 +37,328 @@ func RegisterUserRoutes(router *route.RouterGroup, userClient *UserServiceClient
 	}
 }
 
-// doRequest 发送HTTP请求到用户服务
-func doRequest(c *UserServiceClient, method, path string, body interface{}) (*types.Response, int, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
-			return nil, http.StatusBadRequest, fmt.Errorf("序列化请求失败: %v", err)
-		}
-		reqBody = bytes.NewBuffer(jsonData)
-	}
-
-	url := c.BaseURL + path
-	req, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return nil, http.StatusInternalServerError, fmt.Errorf("创建请求失败: %v", err)
-	}
-
-	// 设置请求头
-	req.Header.Set("Content-Type", "application/json")
-	if c.TenantID != "" {
-		req.Header.Set("X-Tenant-Id", c.TenantID)
-	}
-
-	// 发送请求
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, http.StatusServiceUnavailable, fmt.Errorf("请求用户服务失败: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// 读取响应
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, http.StatusInternalServerError, fmt.Errorf("读取响应失败: %v", err)
+// userID 获取路径中的用户 ID
+func userID(c *app.RequestContext) (string, error) {
+	id := c.Param("id")
+	if id == "" {
+		return "", apperrors.ErrBadRequest("用户 ID 不能为空")
 	}
-
-	// 解析响应
-	var apiResp types.Response
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, http.StatusInternalServerError, fmt.Errorf("解析响应失败: %v, body: %s", err, string(respBody))
-	}
-
-	return &apiResp, resp.StatusCode, nil
+	return id, nil
 }
 
 // handleRegister 用户注册
 func handleRegister(userClient *UserServiceClient) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		var req map[string]interface{}
-		if err := c.BindJSON(&req); err != nil {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "参数错误",
-			})
+		var req dto.RegisterRequest
+		if err := validation.BindJSON(ctx, c, &req); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
 
-		resp, statusCode, err := doRequest(userClient, "POST", "/api/v1/users/register", req)
+		user, err := userClient.Register(ctx, &req)
 		if err != nil {
-			c.JSON(consts.StatusServiceUnavailable, map[string]interface{}{
-				"error": err.Error(),
-			})
-			return
-		}
-
-		if resp.Code != 0 {
-			c.JSON(statusCode, map[string]interface{}{
-				"error": resp.Message,
-			})
+			writeError(ctx, c, err)
 			return
 		}
-
-		c.JSON(consts.StatusOK, map[string]interface{}{
-			"message": "注册成功",
-			"user":    resp.Data,
-		})
+		writeSuccess(ctx, c, "注册成功", user, legacyUser("注册成功"))
 	}
 }
 
 // handleLogin 用户登录
 func handleLogin(userClient *UserServiceClient) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		var req map[string]interface{}
-		if err := c.BindJSON(&req); err != nil {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "参数错误",
-			})
+		var req dto.LoginRequest
+		if err := validation.BindJSON(ctx, c, &req); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
 
-		resp, statusCode, err := doRequest(userClient, "POST", "/api/v1/auth/login", req)
+		result, err := userClient.Login(ctx, &req)
 		if err != nil {
-			c.JSON(consts.StatusServiceUnavailable, map[string]interface{}{
-				"error": err.Error(),
-			})
+			writeError(ctx, c, err)
 			return
 		}
-
-		if resp.Code != 0 {
-			c.JSON(statusCode, map[string]interface{}{
-				"error": resp.Message,
-			})
-			return
-		}
-
-		c.JSON(consts.StatusOK, resp.Data)
+		writeSuccess(ctx, c, "登录成功", result, legacyData)
 	}
 }
 
 // handleLogout 用户登出
 func handleLogout(userClient *UserServiceClient) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		// 从请求头获取 Authorization token
-		token := string(c.GetHeader("Authorization"))
-
-		var req map[string]interface{}
-		if err := c.BindJSON(&req); err != nil {
-			req = make(map[string]interface{})
+		// 请求体可选
+		var req dto.LogoutRequest
+		if len(c.Request.Body()) > 0 {
+			if err := validation.BindJSON(ctx, c, &req); err != nil {
+				writeError(ctx, c, err)
+				return
+			}
 		}
 
-		// 创建带 token 的请求
-		reqBody, _ := json.Marshal(req)
-		httpReq, err := http.NewRequest("POST", userClient.BaseURL+"/api/v1/auth/logout", bytes.NewBuffer(reqBody))
-		if err != nil {
-			c.JSON(consts.StatusInternalServerError, map[string]interface{}{
-				"error": "创建请求失败",
-			})
-			return
-		}
-
-		httpReq.Header.Set("Content-Type", "application/json")
-		if userClient.TenantID != "" {
-			httpReq.Header.Set("X-Tenant-Id", userClient.TenantID)
-		}
-		if token != "" {
-			httpReq.Header.Set("Authorization", token)
-		}
-
-		httpResp, err := userClient.HTTPClient.Do(httpReq)
-		if err != nil {
-			c.JSON(consts.StatusServiceUnavailable, map[string]interface{}{
-				"error": "请求用户服务失败",
-			})
-			return
-		}
-		defer httpResp.Body.Close()
-
-		respBody, _ := io.ReadAll(httpResp.Body)
-		var apiResp types.Response
-		json.Unmarshal(respBody, &apiResp)
-
-		if apiResp.Code != 0 {
-			c.JSON(httpResp.StatusCode, map[string]interface{}{
-				"error": apiResp.Message,
-			})
+		// 转发 Authorization token
+		token := string(c.GetHeader("Authorization"))
+		if err := userClient.Logout(ctx, token, &req); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
-
-		c.JSON(consts.StatusOK, map[string]interface{}{
-			"message": "登出成功",
-		})
+		writeSuccess[any](ctx, c, "登出成功", nil, legacyMessage("登出成功"))
 	}
 }
 
 // handleRefreshToken 刷新 Token
 func handleRefreshToken(userClient *UserServiceClient) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		var req map[string]interface{}
-		if err := c.BindJSON(&req); err != nil {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "参数错误",
-			})
+		var req dto.RefreshTokenRequest
+		if err := validation.BindJSON(ctx, c, &req); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
 
-		resp, statusCode, err := doRequest(userClient, "POST", "/api/v1/auth/refresh", req)
+		token, err := userClient.RefreshToken(ctx, &req)
 		if err != nil {
-			c.JSON(consts.StatusServiceUnavailable, map[string]interface{}{
-				"error": err.Error(),
-			})
+			writeError(ctx, c, err)
 			return
 		}
-
-		if resp.Code != 0 {
-			c.JSON(statusCode, map[string]interface{}{
-				"error": resp.Message,
-			})
-			return
-		}
-
-		c.JSON(consts.StatusOK, resp.Data)
+		writeSuccess(ctx, c, "刷新成功", token, legacyData)
 	}
 }
 
 // handleGetUser 获取用户信息
 func handleGetUser(userClient *UserServiceClient) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		id := c.Param("id")
-		if id == "" {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "用户 ID 不能为空",
-			})
+		id, err := userID(c)
+		if err != nil {
+			writeError(ctx, c, err)
 			return
 		}
 
-		resp, statusCode, err := doRequest(userClient, "GET", "/api/v1/users/"+id, nil)
+		user, err := userClient.GetUser(ctx, id)
 		if err != nil {
-			c.JSON(consts.StatusServiceUnavailable, map[string]interface{}{
-				"error": err.Error(),
-			})
+			writeError(ctx, c, err)
 			return
 		}
+		setUserETag(c, user)
+		writeSuccess(ctx, c, "success", user, legacyData)
+	}
+}
 
-		if resp.Code != 0 {
-			c.JSON(statusCode, map[string]interface{}{
-				"error": resp.Message,
-			})
+// handleUpdateUser 更新用户信息
+func handleUpdateUser(userClient *UserServiceClient) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		id, err := userID(c)
+		if err != nil {
+			writeError(ctx, c, err)
+			return
+		}
+		var req dto.UpdateUserRequest
+		if err := validation.BindJSON(ctx, c, &req); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
 
-		c.JSON(consts.StatusOK, resp.Data)
+		user, err := userClient.UpdateUser(ctx, id, &req)
+		if err != nil {
+			writeError(ctx, c, err)
+			return
+		}
+		setUserETag(c, user)
+		writeSuccess(ctx, c, "更新成功", user, legacyUser("更新成功"))
 	}
 }
 
-// handleUpdateUser 更新用户信息
-func handleUpdateUser(userClient *UserServiceClient) app.HandlerFunc {
+// mergePatchContentType JSON Merge Patch 的媒体类型
+const mergePatchContentType = "application/merge-patch+json"
+
+// updatableUserFields 允许通过补丁修改的用户字段（JSON 字段名）
+var updatableUserFields = []string{"nickname", "email", "phone", "avatar"}
+
+// handlePatchUser 按 JSON Merge Patch（RFC 7396）部分更新用户信息
+// 请求必须为 application/merge-patch+json 并携带 If-Match（取自获取用户信息响应的 ETag），值为 null 表示清空该字段；
+// ETag 与当前用户信息不一致时返回 412，转发给用户服务的更新请求携带 If-Unmodified-Since，
+// 由用户服务拒绝读取之后发生的修改，避免并发补丁相互覆盖
+func handlePatchUser(userClient *UserServiceClient) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		id := c.Param("id")
-		if id == "" {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "用户 ID 不能为空",
-			})
+		id, err := userID(c)
+		if err != nil {
+			writeError(ctx, c, err)
+			return
+		}
+		if mediaType, _, _ := mime.ParseMediaType(string(c.ContentType())); mediaType != mergePatchContentType {
+			writeError(ctx, c, apperrors.FromCode(UserPatchUnsupported))
+			return
+		}
+		ifMatch := string(c.GetHeader("If-Match"))
+		if ifMatch == "" {
+			writeError(ctx, c, apperrors.FromCode(UserPreconditionRequired))
 			return
 		}
 
-		var req map[string]interface{}
-		if err := c.BindJSON(&req); err != nil {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "参数错误",
-			})
+		current, err := userClient.GetUser(ctx, id)
+		if err != nil {
+			writeError(ctx, c, err)
+			return
+		}
+		if current == nil {
+			writeError(ctx, c, apperrors.ErrNotFound("用户不存在"))
+			return
+		}
+		if !etagMatches(ifMatch, userETag(current)) {
+			writeError(ctx, c, apperrors.FromCode(UserPreconditionFailed))
 			return
 		}
 
-		resp, statusCode, err := doRequest(userClient, "PUT", "/api/v1/users/"+id, req)
+		req, fields, err := userPatch(current, c.Request.Body())
 		if err != nil {
-			c.JSON(consts.StatusServiceUnavailable, map[string]interface{}{
-				"error": err.Error(),
-			})
+			writeError(ctx, c, err)
+			return
+		}
+		if req == nil {
+			// 补丁没有修改任何字段
+			setUserETag(c, current)
+			writeSuccess(ctx, c, "更新成功", current, legacyUser("更新成功"))
+			return
+		}
+		if err := validation.StructPartial(ctx, req, fields...); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
 
-		if resp.Code != 0 {
-			c.JSON(statusCode, map[string]interface{}{
-				"error": resp.Message,
-			})
+		user, err := userClient.UpdateUserIfUnmodified(ctx, id, req, *current.UpdatedAt)
+		if err != nil {
+			writeError(ctx, c, err)
 			return
 		}
+		setUserETag(c, user)
+		writeSuccess(ctx, c, "更新成功", user, legacyUser("更新成功"))
+	}
+}
+
+// userPatch 将 JSON Merge Patch 应用到用户信息，返回只包含变化字段的更新请求，没有变化时返回 nil
+// 补丁值先规范化再与当前值比较，只改变首尾空格或邮箱大小写不算修改；
+// fields 为需要校验的字段（结构体字段名），被清空的字段不参与格式校验
+func userPatch(user *dto.UserResponse, doc []byte) (req *dto.UpdateUserRequest, fields []string, err error) {
+	patched := *user
+	changes, err := patch.ApplyMerge(&patched, doc, updatableUserFields...)
+	if err != nil || len(changes) == 0 {
+		return nil, nil, err
+	}
+
+	changed := func(name, value string) *string {
+		if _, ok := changes[name]; !ok {
+			return nil
+		}
+		return &value
+	}
+	req = &dto.UpdateUserRequest{
+		Nickname: changed("Nickname", patched.Nickname),
+		Email:    changed("Email", patched.Email),
+		Phone:    changed("Phone", patched.Phone),
+		Avatar:   changed("Avatar", patched.Avatar),
+	}
+	req.Normalize()
 
-		c.JSON(consts.StatusOK, map[string]interface{}{
-			"message": "更新成功",
-			"user":    resp.Data,
-		})
+	// 规范化后与当前值相同的字段不发送
+	keep := func(value **string, current, name string) {
+		switch {
+		case *value == nil:
+		case **value == current:
+			*value = nil
+		case **value != "":
+			fields = append(fields, name)
+		}
+	}
+	keep(&req.Nickname, user.Nickname, "Nickname")
+	keep(&req.Email, user.Email, "Email")
+	keep(&req.Phone, user.Phone, "Phone")
+	keep(&req.Avatar, user.Avatar, "Avatar")
+	if req.Nickname == nil && req.Email == nil && req.Phone == nil && req.Avatar == nil {
+		return nil, nil, nil
 	}
+	return req, fields, nil
+}
+
+// userETag 用户信息的实体标签，由更新时间生成；用户服务未返回更新时间时为空，此时不支持条件更新
+func userETag(user *dto.UserResponse) string {
+	if user == nil || user.UpdatedAt == nil {
+		return ""
+	}
+	return `"` + strconv.FormatInt(user.UpdatedAt.UnixNano(), 36) + `"`
+}
+
+// setUserETag 在响应头中写入用户信息的 ETag
+func setUserETag(c *app.RequestContext, user *dto.UserResponse) {
+	if etag := userETag(user); etag != "" {
+		c.Header("ETag", etag)
+	}
+}
+
+// etagMatches If-Match 是否与当前 ETag 匹配（强比较，支持逗号分隔的多个值与 *）
+func etagMatches(ifMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
 }
 
 // handleResetPassword 重置密码
 func handleResetPassword(userClient *UserServiceClient) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		var req map[string]interface{}
-		if err := c.BindJSON(&req); err != nil {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "参数错误",
-			})
-			return
-		}
-
-		resp, statusCode, err := doRequest(userClient, "POST", "/api/v1/users/password/reset", req)
-		if err != nil {
-			c.JSON(consts.StatusServiceUnavailable, map[string]interface{}{
-				"error": err.Error(),
-			})
+		var req dto.ResetPasswordRequest
+		if err := validation.BindJSON(ctx, c, &req); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
 
-		if resp.Code != 0 {
-			c.JSON(statusCode, map[string]interface{}{
-				"error": resp.Message,
-			})
+		if err := userClient.ResetPassword(ctx, &req); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
-
-		c.JSON(consts.StatusOK, map[string]interface{}{
-			"message": "密码重置成功",
-		})
+		writeSuccess[any](ctx, c, "密码重置成功", nil, legacyMessage("密码重置成功"))
 	}
 }
 
 // handleSendVerificationCode 发送验证码
 func handleSendVerificationCode(userClient *UserServiceClient) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		var req map[string]interface{}
-		if err := c.BindJSON(&req); err != nil {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "参数错误",
-			})
+		var req dto.SendVerificationCodeRequest
+		if err := validation.BindJSON(ctx, c, &req); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
 
-		resp, statusCode, err := doRequest(userClient, "POST", "/api/v1/verification/code/send", req)
+		result, err := userClient.SendVerificationCode(ctx, &req)
 		if err != nil {
-			c.JSON(consts.StatusServiceUnavailable, map[string]interface{}{
-				"error": err.Error(),
-			})
-			return
-		}
-
-		if resp.Code != 0 {
-			c.JSON(statusCode, map[string]interface{}{
-				"error": resp.Message,
-			})
+			writeError(ctx, c, err)
 			return
 		}
-
-		c.JSON(consts.StatusOK, map[string]interface{}{
-			"message": "验证码已发送",
-		})
+		writeSuccess(ctx, c, "验证码已发送", result, legacyMessage("验证码已发送"))
 	}
 }
 
 // handleVerifyCode 验证验证码
 func handleVerifyCode(userClient *UserServiceClient) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		var req map[string]interface{}
-		if err := c.BindJSON(&req); err != nil {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "参数错误",
-			})
+		var req dto.VerifyCodeRequest
+		if err := validation.BindJSON(ctx, c, &req); err != nil {
+			writeError(ctx, c, err)
 			return
 		}
 
-		resp, statusCode, err := doRequest(userClient, "POST", "/api/v1/verification/code/verify", req)
+		// 用户服务返回验证结果时以 valid 字段为准
+		result, err := userClient.VerifyCode(ctx, &req)
 		if err != nil {
-			c.JSON(consts.StatusServiceUnavailable, map[string]interface{}{
-				"error": err.Error(),
-			})
+			writeError(ctx, c, err)
 			return
 		}
-
-		if resp.Code != 0 {
-			c.JSON(statusCode, map[string]interface{}{
-				"error": resp.Message,
-			})
+		if result != nil && !result.Valid {
+			writeError(ctx, c, apperrors.ErrBadRequest("验证码无效或已过期"))
 			return
 		}
-
-		// 检查验证结果
-		data, ok := resp.Data.(map[string]interface{})
-		if !ok {
-			c.JSON(consts.StatusOK, map[string]interface{}{
-				"message": "验证成功",
-			})
-			return
-		}
-
-		valid, _ := data["valid"].(bool)
-		if !valid {
-			c.JSON(consts.StatusBadRequest, map[string]interface{}{
-				"error": "验证码无效或已过期",
-			})
-			return
-		}
-
-		c.JSON(consts.StatusOK, map[string]interface{}{
-			"message": "验证成功",
-		})
+		writeSuccess(ctx, c, "验证成功", result, legacyMessage("验证成功"))
 	}
 }
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+
+	"soliton-client/api/dto"
+	"soliton-client/share/types"
+)
+
+// upstreamCall 用户服务收到的请求
+type upstreamCall struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   string
+}
+
+// fakeUserService 模拟用户服务，按 "METHOD PATH" 返回预设响应，并记录收到的请求
+type fakeUserService struct {
+	t      *testing.T
+	routes map[string]http.HandlerFunc
+	calls  []upstreamCall
+}
+
+// newFakeUserService 启动模拟用户服务并返回指向它的客户端
+func newFakeUserService(t *testing.T) (*fakeUserService, *UserServiceClient) {
+	t.Helper()
+	f := &fakeUserService{t: t, routes: make(map[string]http.HandlerFunc)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		f.calls = append(f.calls, upstreamCall{Method: r.Method, Path: r.URL.Path, Header: r.Header, Body: string(body)})
+		handler, ok := f.routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected upstream call %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return f, NewUserServiceClient(server.URL, "tenant-1")
+}
+
+// reply 设置成功响应
+func (f *fakeUserService) reply(route string, data interface{}) {
+	f.routes[route] = func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, types.Success(data))
+	}
+}
+
+// replyError 设置错误响应
+func (f *fakeUserService) replyError(route string, status, code int, message string) {
+	f.routes[route] = func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, status, types.Error(code, message))
+	}
+}
+
+// called 返回对指定路由的请求
+func (f *fakeUserService) called(method, path string) []upstreamCall {
+	var calls []upstreamCall
+	for _, call := range f.calls {
+		if call.Method == method && call.Path == path {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// newTestRouter 创建注册了用户路由的测试路由
+func newTestRouter(client *UserServiceClient) *route.Engine {
+	engine := route.NewEngine(config.NewOptions(nil))
+	RegisterUserRoutes(engine.Group("/api/v1"), client)
+	return engine
+}
+
+// perform 发送请求，返回状态码与解析后的响应体
+func perform(t *testing.T, engine *route.Engine, method, path, body string, headers ...ut.Header) (int, map[string]interface{}) {
+	t.Helper()
+	var reqBody *ut.Body
+	if body != "" {
+		reqBody = &ut.Body{Body: bytes.NewBufferString(body), Len: len(body)}
+		// 未指定 Content-Type 时按 JSON 发送
+		hasType := false
+		for _, h := range headers {
+			hasType = hasType || h.Key == "Content-Type"
+		}
+		if !hasType {
+			headers = append(headers, ut.Header{Key: "Content-Type", Value: "application/json"})
+		}
+	}
+	resp := ut.PerformRequest(engine, method, path, reqBody, headers...).Result()
+	var out map[string]interface{}
+	if len(resp.Body()) > 0 {
+		if err := json.Unmarshal(resp.Body(), &out); err != nil {
+			t.Fatalf("decode response %q: %v", resp.Body(), err)
+		}
+	}
+	return resp.StatusCode(), out
+}
+
+func TestUpdateUser(t *testing.T) {
+	updated := map[string]interface{}{"id": 1, "username": "alice", "nickname": "Bob"}
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantForward string // 期望转发给用户服务的请求体，为空表示不转发
+	}{
+		{name: "typed update", body: `{"nickname":" Bob ","email":"Bob@Example.com"}`, wantStatus: http.StatusOK, wantForward: `{"nickname":"Bob","email":"bob@example.com"}`},
+		{name: "empty body", body: `{}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "invalid value", body: `{"email":"not-an-email"}`, wantStatus: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream, client := newFakeUserService(t)
+			upstream.reply("PUT /api/v1/users/1", updated)
+
+			status, body := perform(t, newTestRouter(client), "PUT", "/api/v1/users/1", tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %v", status, tt.wantStatus, body)
+			}
+			puts := upstream.called("PUT", "/api/v1/users/1")
+			if tt.wantForward == "" {
+				if len(puts) != 0 {
+					t.Fatalf("unexpected upstream update: %s", puts[0].Body)
+				}
+				return
+			}
+			if len(puts) != 1 || puts[0].Body != tt.wantForward {
+				t.Fatalf("forwarded = %+v, want %s", puts, tt.wantForward)
+			}
+			// PUT 不携带条件请求头
+			if puts[0].Header.Get("If-Unmodified-Since") != "" {
+				t.Errorf("unexpected precondition %q", puts[0].Header.Get("If-Unmodified-Since"))
+			}
+		})
+	}
+}
+
+func TestPatchUser(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	current := map[string]interface{}{
+		"id": 1, "username": "alice", "email": "alice@example.com", "nickname": "Alice", "avatar": "https://img/a.png",
+		"updated_at": updatedAt,
+	}
+	etag := userETag(&dto.UserResponse{UpdatedAt: &updatedAt})
+	mergePatch := ut.Header{Key: "Content-Type", Value: mergePatchContentType}
+	tests := []struct {
+		name        string
+		patch       string
+		headers     []ut.Header
+		wantStatus  int
+		wantCode    int    // 期望的错误码，0 表示不检查
+		wantForward string // 期望转发给用户服务的请求体，为空表示不转发
+	}{
+		{
+			name:        "changed fields only",
+			patch:       `{"nickname":"Bob","email":"alice@example.com"}`,
+			headers:     []ut.Header{mergePatch, {Key: "If-Match", Value: etag}},
+			wantStatus:  http.StatusOK,
+			wantForward: `{"nickname":"Bob"}`,
+		},
+		{
+			name:        "null clears field",
+			patch:       `{"avatar":null}`,
+			headers:     []ut.Header{mergePatch, {Key: "If-Match", Value: `"other", ` + etag}},
+			wantStatus:  http.StatusOK,
+			wantForward: `{"avatar":""}`,
+		},
+		{
+			name:       "whitespace only is no change",
+			patch:      `{"nickname":" Alice ","email":"ALICE@example.com"}`,
+			headers:    []ut.Header{mergePatch, {Key: "If-Match", Value: etag}},
+			wantStatus: http.StatusOK,
+		},
+		{name: "empty patch", patch: `{}`, headers: []ut.Header{mergePatch, {Key: "If-Match", Value: "*"}}, wantStatus: http.StatusOK},
+		{
+			name:       "json content type rejected",
+			patch:      `{"nickname":"Bob"}`,
+			headers:    []ut.Header{{Key: "If-Match", Value: etag}},
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantCode:   UserPatchUnsupported,
+		},
+		{
+			name:       "missing if-match",
+			patch:      `{"nickname":"Bob"}`,
+			headers:    []ut.Header{mergePatch},
+			wantStatus: http.StatusPreconditionRequired,
+			wantCode:   UserPreconditionRequired,
+		},
+		{
+			name:       "stale etag",
+			patch:      `{"nickname":"Bob"}`,
+			headers:    []ut.Header{mergePatch, {Key: "If-Match", Value: `"stale"`}},
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   UserPreconditionFailed,
+		},
+		{
+			name:       "field not allowed",
+			patch:      `{"username":"root"}`,
+			headers:    []ut.Header{mergePatch, {Key: "If-Match", Value: etag}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid value",
+			patch:      `{"email":"not-an-email"}`,
+			headers:    []ut.Header{mergePatch, {Key: "If-Match", Value: etag}},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream, client := newFakeUserService(t)
+			upstream.reply("GET /api/v1/users/1", current)
+			upstream.reply("PUT /api/v1/users/1", current)
+
+			status, body := perform(t, newTestRouter(client), "PATCH", "/api/v1/users/1", tt.patch, tt.headers...)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %v", status, tt.wantStatus, body)
+			}
+			if tt.wantCode != 0 && body["code"] != float64(tt.wantCode) {
+				t.Fatalf("code = %v, want %d", body["code"], tt.wantCode)
+			}
+			puts := upstream.called("PUT", "/api/v1/users/1")
+			if tt.wantForward == "" {
+				if len(puts) != 0 {
+					t.Fatalf("unexpected upstream update: %s", puts[0].Body)
+				}
+				return
+			}
+			if len(puts) != 1 || puts[0].Body != tt.wantForward {
+				t.Fatalf("forwarded = %+v, want %s", puts, tt.wantForward)
+			}
+			// 用户服务按读取时的更新时间拒绝并发修改
+			if got := puts[0].Header.Get("If-Unmodified-Since"); got != updatedAt.Format(http.TimeFormat) {
+				t.Errorf("If-Unmodified-Since = %q", got)
+			}
+		})
+	}
+}
+
+func TestPatchUserUpstreamPrecondition(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	upstream, client := newFakeUserService(t)
+	upstream.reply("GET /api/v1/users/1", map[string]interface{}{"id": 1, "nickname": "Alice", "updated_at": updatedAt})
+	upstream.replyError("PUT /api/v1/users/1", http.StatusPreconditionFailed, 19999, "modified")
+
+	status, body := perform(t, newTestRouter(client), "PATCH", "/api/v1/users/1", `{"nickname":"Bob"}`,
+		ut.Header{Key: "Content-Type", Value: mergePatchContentType},
+		ut.Header{Key: "If-Match", Value: userETag(&dto.UserResponse{UpdatedAt: &updatedAt})})
+	if status != http.StatusPreconditionFailed || body["code"] != float64(UserPreconditionFailed) {
+		t.Fatalf("status = %d, body = %v", status, body)
+	}
+}
+
+func TestPatchUserNotFound(t *testing.T) {
+	upstream, client := newFakeUserService(t)
+	upstream.replyError("GET /api/v1/users/9", http.StatusNotFound, 10004, "用户不存在")
+
+	status, body := perform(t, newTestRouter(client), "PATCH", "/api/v1/users/9", `{"nickname":"x"}`,
+		ut.Header{Key: "Content-Type", Value: mergePatchContentType}, ut.Header{Key: "If-Match", Value: "*"})
+	if status != http.StatusNotFound || body["code"] != float64(10004) {
+		t.Fatalf("status = %d, body = %v", status, body)
+	}
+	if len(upstream.called("PUT", "/api/v1/users/9")) != 0 {
+		t.Fatal("update forwarded for missing user")
+	}
+}
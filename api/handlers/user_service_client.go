@@ -1,8 +1,18 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
+	"net/url"
 	"time"
+
+	"soliton-client/api/dto"
+	apperrors "soliton-client/share/errors"
+	"soliton-client/share/trace"
+	"soliton-client/share/types"
 )
 
 // UserServiceClient HTTP 客户端
@@ -22,3 +32,136 @@ func NewUserServiceClient(baseURL, tenantID string) *UserServiceClient {
 		},
 	}
 }
+
+// Register 用户注册
+func (c *UserServiceClient) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.UserResponse, error) {
+	return call[*dto.UserResponse](ctx, c, "POST", "/api/v1/users/register", req)
+}
+
+// Login 用户登录
+func (c *UserServiceClient) Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error) {
+	return call[*dto.LoginResponse](ctx, c, "POST", "/api/v1/auth/login", req)
+}
+
+// Logout 用户登出，token 为调用方的 Authorization 请求头
+func (c *UserServiceClient) Logout(ctx context.Context, token string, req *dto.LogoutRequest) error {
+	_, err := doRequest(ctx, c, "POST", "/api/v1/auth/logout", req, withHeader("Authorization", token))
+	return err
+}
+
+// RefreshToken 刷新 Token
+func (c *UserServiceClient) RefreshToken(ctx context.Context, req *dto.RefreshTokenRequest) (*dto.TokenResponse, error) {
+	return call[*dto.TokenResponse](ctx, c, "POST", "/api/v1/auth/refresh", req)
+}
+
+// GetUser 获取用户信息
+func (c *UserServiceClient) GetUser(ctx context.Context, id string) (*dto.UserResponse, error) {
+	return call[*dto.UserResponse](ctx, c, "GET", "/api/v1/users/"+url.PathEscape(id), nil)
+}
+
+// UpdateUser 更新用户信息
+func (c *UserServiceClient) UpdateUser(ctx context.Context, id string, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	return call[*dto.UserResponse](ctx, c, "PUT", "/api/v1/users/"+url.PathEscape(id), req)
+}
+
+// UpdateUserIfUnmodified 有条件地更新用户信息
+// 请求携带 If-Unmodified-Since，用户服务中的用户在 since 之后已被修改时拒绝更新（412）
+func (c *UserServiceClient) UpdateUserIfUnmodified(ctx context.Context, id string, req *dto.UpdateUserRequest, since time.Time) (*dto.UserResponse, error) {
+	return call[*dto.UserResponse](ctx, c, "PUT", "/api/v1/users/"+url.PathEscape(id), req,
+		withHeader("If-Unmodified-Since", since.UTC().Format(http.TimeFormat)))
+}
+
+// ResetPassword 重置密码
+func (c *UserServiceClient) ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	_, err := doRequest(ctx, c, "POST", "/api/v1/users/password/reset", req)
+	return err
+}
+
+// SendVerificationCode 发送验证码
+func (c *UserServiceClient) SendVerificationCode(ctx context.Context, req *dto.SendVerificationCodeRequest) (*dto.SendVerificationCodeResponse, error) {
+	return call[*dto.SendVerificationCodeResponse](ctx, c, "POST", "/api/v1/verification/code/send", req)
+}
+
+// VerifyCode 验证验证码，用户服务未返回验证结果时返回 nil
+func (c *UserServiceClient) VerifyCode(ctx context.Context, req *dto.VerifyCodeRequest) (*dto.VerifyCodeResponse, error) {
+	return call[*dto.VerifyCodeResponse](ctx, c, "POST", "/api/v1/verification/code/verify", req)
+}
+
+// call 调用用户服务并将响应数据解析为 T，响应没有数据时返回零值
+func call[T any](ctx context.Context, c *UserServiceClient, method, path string, body interface{}, opts ...requestOption) (T, error) {
+	var result T
+	data, err := doRequest(ctx, c, method, path, body, opts...)
+	if err != nil || len(data) == 0 || string(data) == "null" {
+		return result, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, transportError(ctx, method, path, err)
+	}
+	return result, nil
+}
+
+// requestOption 用户服务请求选项
+type requestOption func(req *http.Request)
+
+// withHeader 设置请求头，值为空时不设置
+func withHeader(key, value string) requestOption {
+	return func(req *http.Request) {
+		if value != "" {
+			req.Header.Set(key, value)
+		}
+	}
+}
+
+// doRequest 发送HTTP请求到用户服务，请求携带上下文中的链路信息
+// 返回上游响应的数据；上游错误码按映射表转换，传输错误按类型转换为用户模块错误码
+func doRequest(ctx context.Context, c *UserServiceClient, method, path string, body interface{}, opts ...requestOption) (json.RawMessage, error) {
+	var reqBody io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.BadRequest, "序列化请求失败", err)
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reqBody)
+	if err != nil {
+		return nil, apperrors.ErrInternal("创建请求失败", err)
+	}
+
+	// 设置请求头，转发请求 ID 与 traceparent 以便关联日志
+	req.Header.Set("Content-Type", "application/json")
+	trace.Inject(ctx, req.Header)
+	if c.TenantID != "" {
+		req.Header.Set("X-Tenant-Id", c.TenantID)
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	// 发送请求
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, transportError(ctx, method, path, err)
+	}
+	defer resp.Body.Close()
+
+	// 读取响应
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, transportError(ctx, method, path, err)
+	}
+	if len(bytes.TrimSpace(respBody)) == 0 && resp.StatusCode < http.StatusBadRequest {
+		return nil, nil
+	}
+
+	// 解析响应
+	apiResp, err := types.Decode[json.RawMessage](respBody)
+	if err != nil {
+		return nil, transportError(ctx, method, path, err)
+	}
+	if !apiResp.IsSuccess() {
+		return nil, upstreamError(ctx, apiResp, resp.StatusCode)
+	}
+	return apiResp.Data, nil
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cloudwego/hertz/pkg/app"
@@ -16,6 +17,9 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"soliton-client/api/handlers"
+	apperrors "soliton-client/share/errors"
+	"soliton-client/share/jobqueue"
+	"soliton-client/share/trace"
 )
 
 func main() {
@@ -28,6 +32,12 @@ func main() {
 	// 初始化 Redis
 	_ = initRedis()
 
+	// 初始化任务队列
+	workerPool, err := initJobQueue(db)
+	if err != nil {
+		log.Fatalf("初始化任务队列失败: %v", err)
+	}
+
 	// 初始化用户服务HTTP客户端
 	userClient := initUserServiceClient()
 
@@ -38,9 +48,29 @@ func main() {
 		server.WithMaxRequestBodySize(10*1024*1024), // 10MB
 	)
 
+	// 链路 ID、错误恢复与上报，服务关闭时发送队列中剩余的错误报告
+	reporter := initErrorReporter()
+	h.Use(trace.Middleware(), apperrors.Recovery(reporter))
+	h.OnShutdown = append(h.OnShutdown, func(ctx context.Context) {
+		if err := reporter.Close(ctx); err != nil {
+			log.Printf("错误上报队列未发送完成: %v", err)
+		}
+	})
+
+	// 迁移期间可通过 LEGACY_RESPONSE=true 使用旧版响应格式
+	handlers.SetLegacyResponse(getEnv("LEGACY_RESPONSE", "false") == "true")
+
 	// 注册路由
 	registerRoutes(h, db, userClient)
 
+	// 启动任务 worker，服务关闭时等待执行中的任务完成
+	workerPool.Start(context.Background())
+	h.OnShutdown = append(h.OnShutdown, func(ctx context.Context) {
+		if err := workerPool.Stop(ctx); err != nil {
+			log.Printf("任务 worker 停止超时: %v", err)
+		}
+	})
+
 	// 启动服务
 	log.Printf("服务启动在 :%s", port)
 	h.Spin()
@@ -132,6 +162,20 @@ func initRedis() *redis.Client {
 	return client
 }
 
+func initJobQueue(db *gorm.DB) (*jobqueue.WorkerPool, error) {
+	queue := jobqueue.NewQueue(db)
+	if err := queue.Migrate(); err != nil {
+		return nil, err
+	}
+
+	// 任务处理函数在此注册，例如:
+	// jobqueue.Register(queue, "email.send", sendEmail)
+
+	opts := jobqueue.DefaultWorkerOptions()
+	opts.Concurrency = getEnvInt("JOB_WORKERS", opts.Concurrency)
+	return jobqueue.NewWorkerPool(queue, opts), nil
+}
+
 func initUserServiceClient() *handlers.UserServiceClient {
 	// 连接到 universal-service-user 服务
 	baseURL := getEnv("USER_SERVICE_URL", "http://universal-service-user:8080")
@@ -147,6 +191,42 @@ func initUserServiceClient() *handlers.UserServiceClient {
 	return userClient
 }
 
+// initErrorReporter 初始化错误上报器
+// ERROR_REPORT_SINK 以逗号分隔选择接收端：log（默认）、file（ERROR_REPORT_FILE）、sentry（ERROR_REPORT_DSN，可指向本地兼容服务）
+func initErrorReporter() *apperrors.Reporter {
+	opts := apperrors.DefaultReporterOptions()
+	opts.SampleRate = getEnvFloat("ERROR_REPORT_SAMPLE_RATE", opts.SampleRate)
+	if window, err := time.ParseDuration(getEnv("ERROR_REPORT_DEDUP_WINDOW", "")); err == nil {
+		opts.DedupWindow = window
+	}
+
+	var sinks []apperrors.Sink
+	for _, name := range strings.Split(getEnv("ERROR_REPORT_SINK", "log"), ",") {
+		switch strings.TrimSpace(name) {
+		case "log":
+			sinks = append(sinks, apperrors.LogSink{})
+		case "file":
+			sink, err := apperrors.NewFileSink(getEnv("ERROR_REPORT_FILE", "errors.jsonl"))
+			if err != nil {
+				log.Printf("错误上报文件初始化失败: %v", err)
+				continue
+			}
+			sinks = append(sinks, sink)
+		case "sentry":
+			sink, err := apperrors.NewSentrySink(getEnv("ERROR_REPORT_DSN", ""))
+			if err != nil {
+				log.Printf("错误上报 Sentry 初始化失败: %v", err)
+				continue
+			}
+			sinks = append(sinks, sink)
+		case "", "none":
+		default:
+			log.Printf("未知的错误上报接收端: %s", name)
+		}
+	}
+	return apperrors.NewReporter(opts, sinks...)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -162,3 +242,12 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
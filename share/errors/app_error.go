@@ -4,9 +4,25 @@ import "fmt"
 
 // AppError 应用错误基类
 type AppError struct {
-	Code    int    `json:"code"`    // 错误码
-	Message string `json:"message"` // 错误信息
-	Err     error  `json:"-"`       // 原始错误
+	Code       int                    `json:"code"`                 // 错误码
+	Message    string                 `json:"message"`              // 错误信息
+	Violations []FieldViolation       `json:"violations,omitempty"` // 字段校验错误
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`   // 附加信息，如重试时间、冲突的资源 ID
+	Status     int                    `json:"-"`                    // HTTP 状态码，为 0 时按错误码推断
+	Err        error                  `json:"-"`                    // 原始错误
+}
+
+// ErrorDetails 错误详情，随错误响应返回给调用方
+type ErrorDetails struct {
+	Violations []FieldViolation       `json:"violations,omitempty"` // 字段校验错误
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`   // 附加信息
+}
+
+// FieldViolation 字段校验错误
+type FieldViolation struct {
+	Field   string `json:"field"`   // 字段路径，如 email、address.city
+	Rule    string `json:"rule"`    // 违反的校验规则，如 required、email
+	Message string `json:"message"` // 错误描述
 }
 
 func (e *AppError) Error() string {
@@ -21,6 +37,46 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
+// WithStatus 指定 HTTP 状态码（如透传上游服务的状态码），覆盖按错误码推断的状态码
+func (e *AppError) WithStatus(status int) *AppError {
+	e.Status = status
+	return e
+}
+
+// HTTPStatus 错误对应的 HTTP 状态码
+func (e *AppError) HTTPStatus() int {
+	if e.Status != 0 {
+		return e.Status
+	}
+	return getHTTPStatus(e.Code)
+}
+
+// WithViolations 附加字段校验错误
+func (e *AppError) WithViolations(violations ...FieldViolation) *AppError {
+	e.Violations = append(e.Violations, violations...)
+	return e
+}
+
+// WithMetadata 附加信息
+func (e *AppError) WithMetadata(key string, value interface{}) *AppError {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]interface{})
+	}
+	e.Metadata[key] = value
+	return e
+}
+
+// Details 错误详情，没有字段错误与附加信息时返回 nil
+func (e *AppError) Details() *ErrorDetails {
+	if len(e.Violations) == 0 && len(e.Metadata) == 0 {
+		return nil
+	}
+	return &ErrorDetails{
+		Violations: e.Violations,
+		Metadata:   e.Metadata,
+	}
+}
+
 // New 创建新的应用错误
 func New(code int, message string) *AppError {
 	return &AppError{
@@ -51,6 +107,11 @@ const (
 	NotFound      = 10004 // 资源不存在
 	Conflict      = 10005 // 资源冲突
 	InternalError = 10006 // 内部错误
+	Validation    = 10007 // 数据校验失败
+	Timeout       = 10008 // 处理超时
+
+	TooManyRequests    = 10009 // 请求过于频繁
+	ServiceUnavailable = 10010 // 服务暂不可用
 )
 
 // ErrBadRequest 请求参数错误
@@ -82,3 +143,23 @@ func ErrConflict(message string) *AppError {
 func ErrInternal(message string, err error) *AppError {
 	return Wrap(InternalError, message, err)
 }
+
+// ErrValidation 数据校验失败
+func ErrValidation(message string) *AppError {
+	return New(Validation, message)
+}
+
+// ErrTimeout 处理超时
+func ErrTimeout(message string, err error) *AppError {
+	return Wrap(Timeout, message, err)
+}
+
+// ErrTooManyRequests 请求过于频繁
+func ErrTooManyRequests(message string) *AppError {
+	return New(TooManyRequests, message)
+}
+
+// ErrServiceUnavailable 服务暂不可用
+func ErrServiceUnavailable(message string, err error) *AppError {
+	return Wrap(ServiceUnavailable, message, err)
+}
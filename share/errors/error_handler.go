@@ -10,20 +10,42 @@ import (
 )
 
 // HandleError 统一错误处理
-// 支持处理 AppError 及其继承类型（如 UserError）
+// 支持处理 AppError 及其继承类型（如 UserError）；
+// HTTP 状态码取自错误码注册表，消息按 Accept-Language 选择语言；
+// 错误记录到请求上下文供 Recovery 中间件上报；
+// 默认返回统一响应结构，请求 Accept 为 application/problem+json 或配置为 FormatProblem 时返回 RFC 7807 格式
 func HandleError(ctx context.Context, c *app.RequestContext, err error) {
+	locale := NegotiateLocale(string(c.GetHeader("Accept-Language")))
+	c.Header("Content-Language", locale)
+
 	// 使用 errors.As 支持嵌入类型的解包
 	var appErr *AppError
-	if errors.As(err, &appErr) {
-		status := getHTTPStatus(appErr.Code)
-		c.JSON(status, types.Error(appErr.Code, appErr.Message))
+	if !errors.As(err, &appErr) {
+		appErr = FromCode(InternalError)
+	}
+	status := appErr.HTTPStatus()
+
+	// 记录错误供中间件上报
+	if err != nil {
+		_ = c.Error(err)
+	}
+
+	cfg := currentConfig()
+	if wantsProblem(c, cfg) {
+		writeProblem(ctx, c, cfg, NewProblem(appErr, status, locale))
 		return
 	}
 
-	c.JSON(http.StatusInternalServerError, types.Error(InternalError, "内部服务错误"))
+	resp := types.Error(appErr.Code, defaultRegistry.Localize(appErr, locale))
+	resp.TraceID = traceIDOf(ctx, c, cfg)
+	if details := appErr.Details(); details != nil {
+		resp.Details = details
+	}
+	c.JSON(status, resp)
 }
 
 // getHTTPStatus 根据业务错误码获取对应的 HTTP 状态码
+// 优先使用错误码注册表中的状态码；未注册的错误码按末两位兼容旧规则推断。
 // 错误码分段规则:
 //
 //	10000-10999: 通用错误
@@ -31,6 +53,10 @@ func HandleError(ctx context.Context, c *app.RequestContext, err error) {
 //	12000-12999: Order 模块
 //	...以此类推
 func getHTTPStatus(code int) int {
+	if status, ok := defaultRegistry.Status(code); ok {
+		return status
+	}
+
 	// 根据错误码末尾判断类型
 	switch code % 100 {
 	case 1: // xxx01: bad_request
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// handle 调用 HandleError 并返回状态码与解析后的响应体
+func handle(t *testing.T, err error, headers ...string) (*app.RequestContext, map[string]interface{}) {
+	t.Helper()
+	c := app.NewContext(0)
+	c.Request.SetRequestURI("/api/v1/users/1")
+	for i := 0; i+1 < len(headers); i += 2 {
+		c.Request.Header.Set(headers[i], headers[i+1])
+	}
+	HandleError(context.Background(), c, err)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(c.Response.Body(), &body); err != nil {
+		t.Fatalf("decode %q: %v", c.Response.Body(), err)
+	}
+	return c, body
+}
+
+func TestHandleErrorDetails(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		check      func(t *testing.T, details map[string]interface{}) // 为 nil 时期望没有 details
+	}{
+		{
+			name: "violations",
+			err: ErrValidation("email 邮箱格式不正确").
+				WithViolations(FieldViolation{Field: "email", Rule: "email", Message: "邮箱格式不正确"}),
+			wantStatus: http.StatusUnprocessableEntity,
+			check: func(t *testing.T, details map[string]interface{}) {
+				violations, _ := details["violations"].([]interface{})
+				if len(violations) != 1 || violations[0].(map[string]interface{})["field"] != "email" {
+					t.Errorf("violations = %v", details["violations"])
+				}
+			},
+		},
+		{
+			name:       "metadata",
+			err:        ErrTooManyRequests("请求过于频繁").WithMetadata("retry_after", 30),
+			wantStatus: http.StatusTooManyRequests,
+			check: func(t *testing.T, details map[string]interface{}) {
+				metadata, _ := details["metadata"].(map[string]interface{})
+				if metadata["retry_after"] != float64(30) {
+					t.Errorf("metadata = %v", details["metadata"])
+				}
+			},
+		},
+		{name: "no details", err: ErrNotFound("用户不存在"), wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, body := handle(t, tt.err)
+			if c.Response.StatusCode() != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", c.Response.StatusCode(), tt.wantStatus)
+			}
+			details, ok := body["details"].(map[string]interface{})
+			if ok != (tt.check != nil) {
+				t.Fatalf("details = %v", body["details"])
+			}
+			if ok {
+				tt.check(t, details)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 支持的语言
+const (
+	LocaleZhCN    = "zh-CN"
+	LocaleEnUS    = "en-US"
+	DefaultLocale = LocaleZhCN // 默认语言
+)
+
+// languageRange Accept-Language 中的单个语言及其权重
+type languageRange struct {
+	tag     string
+	quality float64
+}
+
+// NegotiateLocale 根据 Accept-Language 请求头选择已注册翻译的语言
+// 先按权重精确匹配（不区分大小写），再按主语言匹配（如 en 匹配 en-US），都不匹配时返回默认语言
+func (r *Registry) NegotiateLocale(acceptLanguage string) string {
+	locales := r.Locales()
+	for _, lr := range parseAcceptLanguage(acceptLanguage) {
+		if lr.tag == "*" {
+			return DefaultLocale
+		}
+		for _, locale := range locales {
+			if strings.EqualFold(locale, lr.tag) {
+				return locale
+			}
+		}
+		primary, _, _ := strings.Cut(lr.tag, "-")
+		for _, locale := range locales {
+			localePrimary, _, _ := strings.Cut(locale, "-")
+			if strings.EqualFold(localePrimary, primary) {
+				return locale
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// NegotiateLocale 根据 Accept-Language 请求头从全局注册表选择语言
+func NegotiateLocale(acceptLanguage string) string {
+	return defaultRegistry.NegotiateLocale(acceptLanguage)
+}
+
+// parseAcceptLanguage 解析 Accept-Language 请求头，按权重降序排列（权重为 0 的忽略）
+func parseAcceptLanguage(header string) []languageRange {
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		ranges = append(ranges, languageRange{tag: tag, quality: quality})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+	return ranges
+}
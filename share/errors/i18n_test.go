@@ -0,0 +1,27 @@
+package errors
+
+import "testing"
+
+func TestNegotiateLocale(t *testing.T) {
+	r := newTestRegistry(t)
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: DefaultLocale},
+		{header: "en-US", want: LocaleEnUS},
+		{header: "en-us", want: LocaleEnUS},
+		{header: "en_GB", want: LocaleEnUS},
+		{header: "fr-FR, en;q=0.8, zh-CN;q=0.9", want: LocaleZhCN},
+		{header: "zh-CN;q=0, en;q=0.1", want: LocaleEnUS},
+		{header: "en;q=abc, fr", want: DefaultLocale},
+		{header: "*", want: DefaultLocale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := r.NegotiateLocale(tt.header); got != tt.want {
+				t.Errorf("NegotiateLocale(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
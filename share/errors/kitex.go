@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+)
+
+// 业务错误附加信息（BizExtra）的键
+const (
+	bizExtraKey        = "key"        // 错误码消息键
+	bizExtraStatus     = "status"     // HTTP 状态码
+	bizExtraViolations = "violations" // 字段校验错误（JSON）
+	bizExtraMetadata   = "metadata"   // 附加信息（JSON）
+)
+
+// ToBizStatusError 将应用错误转换为 Kitex 业务错误
+// 业务状态码即错误码，消息为空时使用注册的默认消息；
+// 错误码消息键、HTTP 状态码、字段校验错误与附加信息放入 BizExtra
+func ToBizStatusError(e *AppError) kerrors.BizStatusErrorIface {
+	message := e.Message
+	if message == "" {
+		message, _ = defaultRegistry.Message(e.Code, DefaultLocale)
+	}
+
+	extra := map[string]string{bizExtraStatus: strconv.Itoa(e.HTTPStatus())}
+	if def, ok := defaultRegistry.Lookup(e.Code); ok {
+		extra[bizExtraKey] = def.Key
+	}
+	if len(e.Violations) > 0 {
+		if raw, err := json.Marshal(e.Violations); err == nil {
+			extra[bizExtraViolations] = string(raw)
+		}
+	}
+	if len(e.Metadata) > 0 {
+		if raw, err := json.Marshal(e.Metadata); err == nil {
+			extra[bizExtraMetadata] = string(raw)
+		}
+	}
+	return kerrors.NewBizStatusErrorWithExtra(int32(e.Code), message, extra)
+}
+
+// FromBizStatusError 将 Kitex 业务错误转换为应用错误，恢复 HTTP 状态码、字段校验错误与附加信息
+func FromBizStatusError(bizErr kerrors.BizStatusErrorIface) *AppError {
+	e := Wrap(int(bizErr.BizStatusCode()), bizErr.BizMessage(), bizErr)
+	extra := bizErr.BizExtra()
+	if status, err := strconv.Atoi(extra[bizExtraStatus]); err == nil && status != getHTTPStatus(e.Code) {
+		e.Status = status
+	}
+	if raw, ok := extra[bizExtraViolations]; ok {
+		var violations []FieldViolation
+		if json.Unmarshal([]byte(raw), &violations) == nil {
+			e.Violations = violations
+		}
+	}
+	if raw, ok := extra[bizExtraMetadata]; ok {
+		var metadata map[string]interface{}
+		if json.Unmarshal([]byte(raw), &metadata) == nil {
+			e.Metadata = metadata
+		}
+	}
+	return e
+}
+
+// FromRPCError 将 RPC 调用返回的错误转换为应用错误
+// 业务错误转换为对应错误码，应用错误原样返回，其他错误（网络、超时等）保持不变
+func FromRPCError(err error) error {
+	if err == nil || IsAppError(err) {
+		return err
+	}
+	if bizErr, ok := kerrors.FromBizStatusError(err); ok {
+		return FromBizStatusError(bizErr)
+	}
+	return err
+}
+
+// KitexServerMiddleware Kitex 服务端中间件
+// 处理器返回的应用错误转换为业务错误写入响应，与 HTTP 接口返回相同的错误码；
+// 需配合 TTHeader 或 gRPC 传输（如 server.WithMetaHandler(transmeta.ServerTTHeaderHandler)）才能传递给客户端
+func KitexServerMiddleware(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, req, resp interface{}) error {
+		err := next(ctx, req, resp)
+		appErr, ok := AsAppError(err)
+		if !ok {
+			return err
+		}
+		ri := rpcinfo.GetRPCInfo(ctx)
+		if ri == nil || ri.Invocation() == nil {
+			return err
+		}
+		if setter, ok := ri.Invocation().(rpcinfo.InvocationSetter); ok {
+			setter.SetBizStatusErr(ToBizStatusError(appErr))
+			return nil
+		}
+		return err
+	}
+}
+
+// KitexClientMiddleware Kitex 客户端中间件
+// 服务端返回的业务错误转换为应用错误，调用方可直接交给 HandleError
+func KitexClientMiddleware(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, req, resp interface{}) error {
+		if err := next(ctx, req, resp); err != nil {
+			return FromRPCError(err)
+		}
+		if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.Invocation() != nil {
+			if bizErr := ri.Invocation().BizStatusErr(); bizErr != nil {
+				return FromBizStatusError(bizErr)
+			}
+		}
+		return nil
+	}
+}
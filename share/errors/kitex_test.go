@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+)
+
+func TestBizStatusErrorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *AppError
+	}{
+		{name: "default message", err: New(NotFound, "")},
+		{
+			name: "violations and metadata",
+			err: ErrValidation("email 邮箱格式不正确").
+				WithViolations(FieldViolation{Field: "email", Rule: "email", Message: "邮箱格式不正确"}).
+				WithMetadata("retry", "no"),
+		},
+		{name: "custom status", err: New(Conflict, "冲突").WithStatus(http.StatusTeapot)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bizErr := ToBizStatusError(tt.err)
+			if int(bizErr.BizStatusCode()) != tt.err.Code || bizErr.BizMessage() == "" {
+				t.Fatalf("biz error = %d %q", bizErr.BizStatusCode(), bizErr.BizMessage())
+			}
+			got := FromBizStatusError(bizErr)
+			if got.Code != tt.err.Code || got.HTTPStatus() != tt.err.HTTPStatus() {
+				t.Errorf("code/status = %d/%d, want %d/%d", got.Code, got.HTTPStatus(), tt.err.Code, tt.err.HTTPStatus())
+			}
+			if !reflect.DeepEqual(got.Violations, tt.err.Violations) {
+				t.Errorf("violations = %+v, want %+v", got.Violations, tt.err.Violations)
+			}
+			if len(tt.err.Metadata) > 0 && !reflect.DeepEqual(got.Metadata, tt.err.Metadata) {
+				t.Errorf("metadata = %+v, want %+v", got.Metadata, tt.err.Metadata)
+			}
+		})
+	}
+}
+
+func TestFromRPCError(t *testing.T) {
+	network := errors.New("connection refused")
+	appErr := ErrNotFound("用户不存在")
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int // 0 表示期望原样返回
+	}{
+		{name: "nil", err: nil},
+		{name: "network error unchanged", err: network},
+		{name: "app error unchanged", err: appErr},
+		{name: "biz error converted", err: kerrors.NewBizStatusError(int32(Conflict), "冲突"), wantCode: Conflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromRPCError(tt.err)
+			if tt.wantCode == 0 {
+				if got != tt.err {
+					t.Fatalf("err = %v, want %v", got, tt.err)
+				}
+				return
+			}
+			if e, ok := AsAppError(got); !ok || e.Code != tt.wantCode {
+				t.Fatalf("err = %v, want code %d", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+// rpcContext 返回带指定调用信息的上下文，withRPCInfo 为 false 时不带 RPCInfo
+func rpcContext(withRPCInfo bool, ink rpcinfo.Invocation) context.Context {
+	if !withRPCInfo {
+		return context.Background()
+	}
+	ri := rpcinfo.NewRPCInfo(nil, nil, ink, nil, nil)
+	return rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+}
+
+func TestKitexServerMiddleware(t *testing.T) {
+	appErr := ErrNotFound("用户不存在")
+	plain := errors.New("plain")
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		err         error
+		wantErr     error
+		wantBizCode int // 0 表示期望不写入业务错误
+	}{
+		{name: "app error to biz error", ctx: rpcContext(true, rpcinfo.NewServerInvocation()), err: appErr, wantBizCode: NotFound},
+		{name: "plain error unchanged", ctx: rpcContext(true, rpcinfo.NewServerInvocation()), err: plain, wantErr: plain},
+		{name: "no rpc info", ctx: rpcContext(false, nil), err: appErr, wantErr: appErr},
+		{name: "no invocation", ctx: rpcContext(true, nil), err: appErr, wantErr: appErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := endpoint.Endpoint(func(context.Context, interface{}, interface{}) error { return tt.err })
+			err := KitexServerMiddleware(next)(tt.ctx, nil, nil)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantBizCode == 0 {
+				return
+			}
+			bizErr := rpcinfo.GetRPCInfo(tt.ctx).Invocation().BizStatusErr()
+			if bizErr == nil || int(bizErr.BizStatusCode()) != tt.wantBizCode {
+				t.Fatalf("biz error = %v, want code %d", bizErr, tt.wantBizCode)
+			}
+		})
+	}
+}
+
+func TestKitexClientMiddleware(t *testing.T) {
+	withBizErr := rpcinfo.NewServerInvocation()
+	withBizErr.(rpcinfo.InvocationSetter).SetBizStatusErr(kerrors.NewBizStatusError(int32(Conflict), "冲突"))
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		wantCode int // 0 表示期望成功
+	}{
+		{name: "success", ctx: rpcContext(true, rpcinfo.NewServerInvocation())},
+		{name: "biz error in invocation", ctx: rpcContext(true, withBizErr), wantCode: Conflict},
+		{name: "biz error returned", ctx: rpcContext(false, nil), err: kerrors.NewBizStatusError(int32(NotFound), "x"), wantCode: NotFound},
+		{name: "no invocation", ctx: rpcContext(true, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := endpoint.Endpoint(func(context.Context, interface{}, interface{}) error { return tt.err })
+			err := KitexClientMiddleware(next)(tt.ctx, nil, nil)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+			if e, ok := AsAppError(err); !ok || e.Code != tt.wantCode {
+				t.Fatalf("err = %v, want code %d", err, tt.wantCode)
+			}
+		})
+	}
+}
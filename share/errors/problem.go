@@ -0,0 +1,146 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"soliton-client/share/trace"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// ProblemContentType RFC 7807 错误响应的媒体类型
+const ProblemContentType = "application/problem+json"
+
+// DefaultProblemTypeBase 默认的问题类型 URI 前缀，与错误码消息键拼接为类型 URI
+const DefaultProblemTypeBase = "/problems/"
+
+// ErrorFormat 错误响应格式
+type ErrorFormat string
+
+// 错误响应格式
+const (
+	FormatEnvelope ErrorFormat = "envelope" // 统一响应结构 types.Response（默认）
+	FormatProblem  ErrorFormat = "problem"  // RFC 7807 application/problem+json
+)
+
+// HandlerConfig 错误处理配置
+type HandlerConfig struct {
+	Format          ErrorFormat                                             // 默认响应格式；请求 Accept 包含 application/problem+json 时总是使用 RFC 7807
+	ProblemTypeBase string                                                  // 问题类型 URI 前缀，如 https://docs.example.com/errors/
+	TraceID         func(ctx context.Context, c *app.RequestContext) string // 获取链路 ID，为空时使用 trace 中间件保存的链路 ID
+}
+
+// Problem RFC 7807 问题详情
+type Problem struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Code       int                    `json:"code"`                 // 扩展成员：业务错误码
+	TraceID    string                 `json:"trace_id,omitempty"`   // 扩展成员：链路 ID
+	Violations []FieldViolation       `json:"violations,omitempty"` // 扩展成员：字段校验错误
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`   // 扩展成员：附加信息
+}
+
+var (
+	handlerMu     sync.RWMutex
+	handlerConfig = HandlerConfig{Format: FormatEnvelope, ProblemTypeBase: DefaultProblemTypeBase}
+)
+
+// Configure 设置错误处理配置，未设置的字段使用默认值
+func Configure(cfg HandlerConfig) {
+	if cfg.Format == "" {
+		cfg.Format = FormatEnvelope
+	}
+	if cfg.ProblemTypeBase == "" {
+		cfg.ProblemTypeBase = DefaultProblemTypeBase
+	}
+	handlerMu.Lock()
+	defer handlerMu.Unlock()
+	handlerConfig = cfg
+}
+
+// currentConfig 当前错误处理配置
+func currentConfig() HandlerConfig {
+	handlerMu.RLock()
+	defer handlerMu.RUnlock()
+	return handlerConfig
+}
+
+// NewProblem 将应用错误转换为 RFC 7807 问题详情
+// type 由类型 URI 前缀与注册的消息键拼接，未注册的错误码为 about:blank；
+// title 为错误码在该语言下的注册消息，detail 为错误的具体消息
+func NewProblem(e *AppError, status int, locale string) *Problem {
+	cfg := currentConfig()
+	problem := &Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     defaultRegistry.Localize(e, locale),
+		Code:       e.Code,
+		Violations: e.Violations,
+		Metadata:   e.Metadata,
+	}
+	if def, ok := defaultRegistry.Lookup(e.Code); ok {
+		problem.Type = cfg.ProblemTypeBase + def.Key
+		if title, ok := defaultRegistry.Message(e.Code, locale); ok {
+			problem.Title = title
+		}
+	}
+	return problem
+}
+
+// wantsProblem 是否以 RFC 7807 格式响应：请求 Accept 明确接受 application/problem+json 或配置为该格式
+func wantsProblem(c *app.RequestContext, cfg HandlerConfig) bool {
+	if cfg.Format == FormatProblem {
+		return true
+	}
+	for _, part := range strings.Split(string(c.GetHeader("Accept")), ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(mediaType), ProblemContentType) {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if quality, err := strconv.ParseFloat(q, 64); err != nil || quality <= 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// writeProblem 以 application/problem+json 写出问题详情
+func writeProblem(ctx context.Context, c *app.RequestContext, cfg HandlerConfig, problem *Problem) {
+	problem.Instance = string(c.Request.URI().Path())
+	problem.TraceID = traceIDOf(ctx, c, cfg)
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(problem.Status, ProblemContentType+"; charset=utf-8", body)
+}
+
+// traceIDOf 获取请求的链路 ID
+func traceIDOf(ctx context.Context, c *app.RequestContext, cfg HandlerConfig) string {
+	if cfg.TraceID != nil {
+		return cfg.TraceID(ctx, c)
+	}
+	return trace.TraceID(ctx)
+}
+
+// requestIDOf 获取请求 ID，未经过 trace 中间件时读取请求头
+func requestIDOf(ctx context.Context, c *app.RequestContext) string {
+	if id := trace.RequestID(ctx); id != "" {
+		return id
+	}
+	return string(c.GetHeader(trace.HeaderRequestID))
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// configure 在测试期间使用指定的错误处理配置
+func configure(t *testing.T, cfg HandlerConfig) {
+	t.Helper()
+	Configure(cfg)
+	t.Cleanup(func() { Configure(HandlerConfig{}) })
+}
+
+func TestWantsProblem(t *testing.T) {
+	tests := []struct {
+		accept string
+		format ErrorFormat
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "application/json", want: false},
+		{accept: "application/problem+json", want: true},
+		{accept: "application/json, Application/Problem+JSON;q=0.5", want: true},
+		{accept: "application/problem+json;q=0", want: false},
+		{accept: "application/json", format: FormatProblem, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept+"/"+string(tt.format), func(t *testing.T) {
+			c := app.NewContext(0)
+			c.Request.Header.Set("Accept", tt.accept)
+			if got := wantsProblem(c, HandlerConfig{Format: tt.format}); got != tt.want {
+				t.Errorf("wantsProblem = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleErrorProblem(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		cfg       HandlerConfig
+		headers   []string
+		want      map[string]interface{}
+		wantTrace string
+	}{
+		{
+			name:    "registered code",
+			err:     ErrNotFound("用户 1 不存在"),
+			headers: []string{"Accept", ProblemContentType, "Accept-Language", "en-US"},
+			want: map[string]interface{}{
+				"type": "/problems/common.not_found", "title": "Resource not found", "status": float64(404),
+				"detail": "用户 1 不存在", "instance": "/api/v1/users/1", "code": float64(NotFound),
+			},
+		},
+		{
+			name: "configured format and type base",
+			err:  ErrValidation("").WithViolations(FieldViolation{Field: "email", Rule: "email", Message: "邮箱格式不正确"}),
+			cfg: HandlerConfig{
+				Format:          FormatProblem,
+				ProblemTypeBase: "https://docs.example.com/errors/",
+				TraceID:         func(context.Context, *app.RequestContext) string { return "trace-1" },
+			},
+			want: map[string]interface{}{
+				"type": "https://docs.example.com/errors/common.validation", "title": "数据校验失败",
+				"detail": "数据校验失败", "status": float64(422),
+			},
+			wantTrace: "trace-1",
+		},
+		{
+			name:    "unregistered code",
+			err:     New(19999, "未知错误"),
+			headers: []string{"Accept", ProblemContentType},
+			want:    map[string]interface{}{"type": "about:blank", "title": "Internal Server Error", "status": float64(500)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configure(t, tt.cfg)
+			c, body := handle(t, tt.err, tt.headers...)
+			if ct := string(c.Response.Header.ContentType()); ct != ProblemContentType+"; charset=utf-8" {
+				t.Fatalf("content type = %q", ct)
+			}
+			if status := c.Response.StatusCode(); float64(status) != tt.want["status"] {
+				t.Fatalf("status = %d, want %v", status, tt.want["status"])
+			}
+			for key, want := range tt.want {
+				if body[key] != want {
+					t.Errorf("%s = %v, want %v", key, body[key], want)
+				}
+			}
+			if tt.wantTrace != "" && body["trace_id"] != tt.wantTrace {
+				t.Errorf("trace_id = %v, want %s", body["trace_id"], tt.wantTrace)
+			}
+		})
+	}
+
+	// 默认仍返回统一响应结构
+	c, body := handle(t, ErrNotFound("用户不存在"))
+	if c.Response.StatusCode() != http.StatusNotFound || body["code"] != float64(NotFound) || body["type"] != nil {
+		t.Fatalf("envelope = %v", body)
+	}
+}
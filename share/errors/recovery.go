@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Recovery 错误恢复与上报中间件
+// 处理器 panic 时恢复为 InternalError 并经 HandleError 响应；
+// 响应状态码为 5xx 时（包括 panic）附带请求 ID 经 Reporter 的有界队列异步上报，panic 同时附带调用栈；
+// reporter 为 nil 时只恢复不上报
+func Recovery(reporter *Reporter) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		var stack []byte
+		defer func() {
+			if r := recover(); r != nil {
+				stack = debug.Stack()
+				HandleError(ctx, c, ErrInternal("", fmt.Errorf("panic: %v", r)))
+				c.Abort()
+			}
+			if reporter != nil && c.Response.StatusCode() >= http.StatusInternalServerError {
+				reporter.Enqueue(ctx, newReport(ctx, c, stack))
+			}
+		}()
+		c.Next(ctx)
+	}
+}
+
+// newReport 根据请求上下文生成错误报告（需在请求处理期间调用），stack 为 panic 时的调用栈
+func newReport(ctx context.Context, c *app.RequestContext, stack []byte) *Report {
+	report := &Report{
+		Time:      time.Now(),
+		Panic:     stack != nil,
+		Stack:     string(stack),
+		Status:    c.Response.StatusCode(),
+		Method:    string(c.Method()),
+		Path:      string(c.Request.URI().Path()),
+		Route:     c.FullPath(),
+		RequestID: requestIDOf(ctx, c),
+	}
+	if last := c.Errors.Last(); last != nil && last.Err != nil {
+		report.Error = last.Err.Error()
+		if appErr, ok := AsAppError(last.Err); ok {
+			report.Code = appErr.Code
+			report.Message = appErr.Message
+			if report.Message == "" {
+				report.Message, _ = defaultRegistry.Message(appErr.Code, DefaultLocale)
+			}
+		}
+	}
+	return report
+}
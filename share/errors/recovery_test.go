@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+func TestRecovery(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    app.HandlerFunc
+		wantStatus int
+		wantReport bool
+		wantPanic  bool
+	}{
+		{
+			name:       "panic",
+			handler:    func(context.Context, *app.RequestContext) { panic("boom") },
+			wantStatus: http.StatusInternalServerError,
+			wantReport: true,
+			wantPanic:  true,
+		},
+		{
+			name: "handled 5xx has no stack",
+			handler: func(ctx context.Context, c *app.RequestContext) {
+				HandleError(ctx, c, ErrInternal("", errors.New("db down")))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantReport: true,
+		},
+		{
+			name: "4xx not reported",
+			handler: func(ctx context.Context, c *app.RequestContext) {
+				HandleError(ctx, c, ErrNotFound("用户不存在"))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			reporter := NewReporter(&ReporterOptions{SampleRate: 1}, sink)
+			c := app.NewContext(0)
+			c.Request.SetRequestURI("/api/v1/users/1")
+			c.SetHandlers(app.HandlersChain{Recovery(reporter), tt.handler})
+			c.Next(context.Background())
+
+			if err := reporter.Close(context.Background()); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+			if got := c.Response.StatusCode(); got != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", got, tt.wantStatus)
+			}
+			if !tt.wantReport {
+				if sink.count() != 0 {
+					t.Fatalf("reports = %d, want 0", sink.count())
+				}
+				return
+			}
+			if sink.count() != 1 {
+				t.Fatalf("reports = %d, want 1", sink.count())
+			}
+			report := sink.reports[0]
+			if report.Panic != tt.wantPanic || report.Path != "/api/v1/users/1" || report.Status != tt.wantStatus {
+				t.Errorf("report = %+v", report)
+			}
+			// panic 报告附带 panic 处的调用栈，普通 5xx 不附带
+			if tt.wantPanic != strings.Contains(report.Stack, "recovery_test.go") {
+				t.Errorf("stack = %q, want panic %v", report.Stack, tt.wantPanic)
+			}
+		})
+	}
+}
+
+func TestRecoveryWithoutReporter(t *testing.T) {
+	c := app.NewContext(0)
+	c.SetHandlers(app.HandlersChain{Recovery(nil), func(context.Context, *app.RequestContext) { panic("boom") }})
+	c.Next(context.Background())
+	if got := c.Response.StatusCode(); got != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", got)
+	}
+}
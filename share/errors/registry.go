@@ -0,0 +1,244 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Module 错误码模块，每个模块独占一个号段
+type Module struct {
+	Name string // 模块名
+	Min  int    // 号段起始（含）
+	Max  int    // 号段结束（含）
+}
+
+// 错误码号段
+var (
+	ModuleCommon = Module{Name: "common", Min: 10000, Max: 10999} // 通用错误
+	ModuleUser   = Module{Name: "user", Min: 11000, Max: 11999}   // User 模块
+	ModuleOrder  = Module{Name: "order", Min: 12000, Max: 12999}  // Order 模块
+)
+
+// Contains 错误码是否属于该模块号段
+func (m Module) Contains(code int) bool {
+	return code >= m.Min && code <= m.Max
+}
+
+// Definition 错误码定义
+type Definition struct {
+	Code     int               // 错误码
+	Status   int               // HTTP 状态码
+	Key      string            // 消息键，如 user.not_found
+	Messages map[string]string // 各语言的默认消息（语言 -> 消息），如 zh-CN、en-US
+}
+
+// Registry 错误码注册表
+// 记录错误码对应的 HTTP 状态码与多语言消息，注册时校验号段与重复
+type Registry struct {
+	mu       sync.RWMutex
+	modules  map[string]Module
+	defs     map[int]Definition
+	messages map[string]map[string]string // 语言 -> 消息键 -> 消息
+}
+
+// NewRegistry 创建错误码注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		modules:  make(map[string]Module),
+		defs:     make(map[int]Definition),
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// Register 注册模块的错误码
+// 错误码必须位于模块号段内且未被注册，任一定义不合法时整体不生效
+func (r *Registry) Register(module Module, defs ...Definition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkModule(module); err != nil {
+		return err
+	}
+	seen := make(map[int]struct{}, len(defs))
+	for _, def := range defs {
+		if !module.Contains(def.Code) {
+			return fmt.Errorf("error code %d is outside module %s range %d-%d", def.Code, module.Name, module.Min, module.Max)
+		}
+		if _, dup := r.defs[def.Code]; dup {
+			return fmt.Errorf("error code %d is already registered", def.Code)
+		}
+		if _, dup := seen[def.Code]; dup {
+			return fmt.Errorf("error code %d is registered twice", def.Code)
+		}
+		if def.Status < 100 || def.Status > 599 {
+			return fmt.Errorf("error code %d has invalid http status %d", def.Code, def.Status)
+		}
+		if def.Key == "" {
+			return fmt.Errorf("error code %d has no message key", def.Code)
+		}
+		seen[def.Code] = struct{}{}
+	}
+
+	r.modules[module.Name] = module
+	for _, def := range defs {
+		r.defs[def.Code] = def
+		for locale, message := range def.Messages {
+			r.setMessage(locale, def.Key, message)
+		}
+	}
+	return nil
+}
+
+// MustRegister 注册模块的错误码，失败时 panic（用于包初始化）
+func (r *Registry) MustRegister(module Module, defs ...Definition) {
+	if err := r.Register(module, defs...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterMessages 注册某种语言的消息翻译（消息键 -> 消息）
+func (r *Registry) RegisterMessages(locale string, messages map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, message := range messages {
+		r.setMessage(locale, key, message)
+	}
+}
+
+// Lookup 查询错误码定义
+func (r *Registry) Lookup(code int) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, ok := r.defs[code]
+	return def, ok
+}
+
+// Status 错误码对应的 HTTP 状态码，未注册的错误码返回 false
+func (r *Registry) Status(code int) (int, bool) {
+	def, ok := r.Lookup(code)
+	return def.Status, ok
+}
+
+// Message 错误码在指定语言下的消息，没有该语言的翻译时使用默认语言
+func (r *Registry) Message(code int, locale string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.defs[code]
+	if !ok {
+		return "", false
+	}
+	if message, ok := r.messages[locale][def.Key]; ok {
+		return message, true
+	}
+	message, ok := r.messages[DefaultLocale][def.Key]
+	return message, ok
+}
+
+// Locales 已注册翻译的语言
+func (r *Registry) Locales() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	locales := make([]string, 0, len(r.messages))
+	for locale := range r.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Localize 按语言选择错误消息
+// 错误使用注册的默认消息（或消息为空）时返回对应语言的翻译；
+// 调用方传入的自定义消息包含具体信息，保持不变
+func (r *Registry) Localize(e *AppError, locale string) string {
+	defaultMessage, ok := r.Message(e.Code, DefaultLocale)
+	if !ok || (e.Message != "" && e.Message != defaultMessage) {
+		return e.Message
+	}
+	if message, ok := r.Message(e.Code, locale); ok {
+		return message
+	}
+	return e.Message
+}
+
+// checkModule 校验模块号段，同名模块号段必须一致且不能与其他模块重叠
+func (r *Registry) checkModule(module Module) error {
+	if module.Name == "" || module.Min > module.Max {
+		return fmt.Errorf("invalid error module %q range %d-%d", module.Name, module.Min, module.Max)
+	}
+	for name, existing := range r.modules {
+		if name == module.Name {
+			if existing != module {
+				return fmt.Errorf("error module %s is already registered with range %d-%d", name, existing.Min, existing.Max)
+			}
+			continue
+		}
+		if module.Min <= existing.Max && existing.Min <= module.Max {
+			return fmt.Errorf("error module %s range %d-%d overlaps module %s", module.Name, module.Min, module.Max, name)
+		}
+	}
+	return nil
+}
+
+// setMessage 设置消息（调用方持有写锁）
+func (r *Registry) setMessage(locale, key, message string) {
+	if r.messages[locale] == nil {
+		r.messages[locale] = make(map[string]string)
+	}
+	r.messages[locale][key] = message
+}
+
+// defaultRegistry 全局错误码注册表
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry 获取全局错误码注册表
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register 向全局注册表注册模块的错误码
+func Register(module Module, defs ...Definition) error {
+	return defaultRegistry.Register(module, defs...)
+}
+
+// MustRegister 向全局注册表注册模块的错误码，失败时 panic
+func MustRegister(module Module, defs ...Definition) {
+	defaultRegistry.MustRegister(module, defs...)
+}
+
+// FromCode 使用注册的默认消息创建应用错误
+func FromCode(code int) *AppError {
+	message, _ := defaultRegistry.Message(code, DefaultLocale)
+	return New(code, message)
+}
+
+func init() {
+	// 预留业务模块号段
+	MustRegister(ModuleUser)
+	MustRegister(ModuleOrder)
+
+	MustRegister(ModuleCommon,
+		Definition{Code: BadRequest, Status: http.StatusBadRequest, Key: "common.bad_request",
+			Messages: map[string]string{LocaleZhCN: "请求参数错误", LocaleEnUS: "Bad request"}},
+		Definition{Code: Unauthorized, Status: http.StatusUnauthorized, Key: "common.unauthorized",
+			Messages: map[string]string{LocaleZhCN: "未授权", LocaleEnUS: "Unauthorized"}},
+		Definition{Code: Forbidden, Status: http.StatusForbidden, Key: "common.forbidden",
+			Messages: map[string]string{LocaleZhCN: "禁止访问", LocaleEnUS: "Forbidden"}},
+		Definition{Code: NotFound, Status: http.StatusNotFound, Key: "common.not_found",
+			Messages: map[string]string{LocaleZhCN: "资源不存在", LocaleEnUS: "Resource not found"}},
+		Definition{Code: Conflict, Status: http.StatusConflict, Key: "common.conflict",
+			Messages: map[string]string{LocaleZhCN: "资源冲突", LocaleEnUS: "Resource conflict"}},
+		Definition{Code: InternalError, Status: http.StatusInternalServerError, Key: "common.internal_error",
+			Messages: map[string]string{LocaleZhCN: "内部服务错误", LocaleEnUS: "Internal server error"}},
+		Definition{Code: Validation, Status: http.StatusUnprocessableEntity, Key: "common.validation",
+			Messages: map[string]string{LocaleZhCN: "数据校验失败", LocaleEnUS: "Validation failed"}},
+		Definition{Code: Timeout, Status: http.StatusGatewayTimeout, Key: "common.timeout",
+			Messages: map[string]string{LocaleZhCN: "处理超时", LocaleEnUS: "Request timed out"}},
+		Definition{Code: TooManyRequests, Status: http.StatusTooManyRequests, Key: "common.too_many_requests",
+			Messages: map[string]string{LocaleZhCN: "请求过于频繁，请稍后再试", LocaleEnUS: "Too many requests, please try again later"}},
+		Definition{Code: ServiceUnavailable, Status: http.StatusServiceUnavailable, Key: "common.service_unavailable",
+			Messages: map[string]string{LocaleZhCN: "服务暂不可用", LocaleEnUS: "Service unavailable"}},
+	)
+}
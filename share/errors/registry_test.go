@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+// testModule 测试用模块号段
+var testModule = Module{Name: "test", Min: 90000, Max: 90999}
+
+// newTestRegistry 创建注册了测试模块的注册表
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	err := r.Register(testModule,
+		Definition{Code: 90001, Status: http.StatusBadRequest, Key: "test.bad",
+			Messages: map[string]string{LocaleZhCN: "参数错误", LocaleEnUS: "Bad input"}},
+		Definition{Code: 90002, Status: http.StatusNotFound, Key: "test.missing",
+			Messages: map[string]string{LocaleZhCN: "不存在"}},
+	)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	return r
+}
+
+func TestRegistryRegister(t *testing.T) {
+	tests := []struct {
+		name   string
+		module Module
+		defs   []Definition
+	}{
+		{name: "code outside range", module: testModule, defs: []Definition{{Code: 91000, Status: 400, Key: "k"}}},
+		{name: "already registered", module: testModule, defs: []Definition{{Code: 90001, Status: 400, Key: "k"}}},
+		{name: "duplicate in call", module: testModule, defs: []Definition{{Code: 90010, Status: 400, Key: "k"}, {Code: 90010, Status: 400, Key: "k"}}},
+		{name: "invalid status", module: testModule, defs: []Definition{{Code: 90011, Status: 700, Key: "k"}}},
+		{name: "missing key", module: testModule, defs: []Definition{{Code: 90012, Status: 400}}},
+		{name: "module range changed", module: Module{Name: "test", Min: 90000, Max: 90500}},
+		{name: "module overlaps", module: Module{Name: "other", Min: 90900, Max: 91100}},
+		{name: "invalid module", module: Module{Name: "bad", Min: 5, Max: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRegistry(t)
+			if err := r.Register(tt.module, tt.defs...); err == nil {
+				t.Fatal("register should fail")
+			}
+			// 失败时不注册任何定义
+			for _, def := range tt.defs {
+				if _, ok := r.Lookup(def.Code); ok && def.Code != 90001 {
+					t.Errorf("code %d registered after failure", def.Code)
+				}
+			}
+		})
+	}
+
+	r := newTestRegistry(t)
+	if err := r.Register(testModule, Definition{Code: 90003, Status: 409, Key: "test.conflict"}); err != nil {
+		t.Fatalf("register more codes in same module: %v", err)
+	}
+	if status, ok := r.Status(90003); !ok || status != http.StatusConflict {
+		t.Fatalf("status = %d, %v", status, ok)
+	}
+}
+
+func TestRegistryLocalize(t *testing.T) {
+	r := newTestRegistry(t)
+	r.RegisterMessages("ja-JP", map[string]string{"test.bad": "不正な入力"})
+
+	tests := []struct {
+		name   string
+		err    *AppError
+		locale string
+		want   string
+	}{
+		{name: "default message translated", err: New(90001, "参数错误"), locale: LocaleEnUS, want: "Bad input"},
+		{name: "empty message translated", err: New(90001, ""), locale: "ja-JP", want: "不正な入力"},
+		{name: "missing translation falls back", err: New(90002, ""), locale: LocaleEnUS, want: "不存在"},
+		{name: "custom message kept", err: New(90001, "邮箱格式不正确"), locale: LocaleEnUS, want: "邮箱格式不正确"},
+		{name: "unregistered code kept", err: New(99999, "原始消息"), locale: LocaleEnUS, want: "原始消息"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Localize(tt.err, tt.locale); got != tt.want {
+				t.Errorf("Localize = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code int
+		want int
+	}{
+		{code: Validation, want: http.StatusUnprocessableEntity},
+		{code: Timeout, want: http.StatusGatewayTimeout},
+		{code: TooManyRequests, want: http.StatusTooManyRequests},
+		{code: ServiceUnavailable, want: http.StatusServiceUnavailable},
+		// 未注册的错误码按末两位推断
+		{code: 11004, want: http.StatusNotFound},
+		{code: 12005, want: http.StatusConflict},
+		{code: 12099, want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := getHTTPStatus(tt.code); got != tt.want {
+			t.Errorf("getHTTPStatus(%d) = %d, want %d", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+)
+
+// Report 错误报告
+type Report struct {
+	Time       time.Time `json:"time"`
+	Panic      bool      `json:"panic"`                // 是否由 panic 恢复
+	Status     int       `json:"status"`               // HTTP 状态码
+	Code       int       `json:"code,omitempty"`       // 业务错误码
+	Message    string    `json:"message,omitempty"`    // 错误消息
+	Error      string    `json:"error,omitempty"`      // 原始错误
+	Method     string    `json:"method"`               // 请求方法
+	Path       string    `json:"path"`                 // 请求路径
+	Route      string    `json:"route,omitempty"`      // 路由模板，如 /api/v1/users/:id
+	RequestID  string    `json:"request_id,omitempty"` // 请求 ID
+	Stack      string    `json:"stack,omitempty"`      // 调用栈
+	Suppressed int       `json:"suppressed,omitempty"` // 上次上报后被去重丢弃的次数
+}
+
+// Fingerprint 错误指纹，用于去重：相同路由、状态码、错误码与错误消息视为同一错误
+func (r *Report) Fingerprint() string {
+	route := r.Route
+	if route == "" {
+		route = r.Path
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("%t|%s|%s|%d|%d|%s", r.Panic, r.Method, route, r.Status, r.Code, r.Error)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Sink 错误报告接收端
+type Sink interface {
+	Send(ctx context.Context, report *Report) error
+}
+
+// SinkFunc 函数形式的错误报告接收端
+type SinkFunc func(ctx context.Context, report *Report) error
+
+// Send 发送错误报告
+func (f SinkFunc) Send(ctx context.Context, report *Report) error {
+	return f(ctx, report)
+}
+
+// LogSink 输出到日志的接收端
+type LogSink struct{}
+
+// Send 记录错误报告
+func (LogSink) Send(ctx context.Context, report *Report) error {
+	kind := "error"
+	if report.Panic {
+		kind = "panic"
+	}
+	hlog.CtxErrorf(ctx, "%s: %s %s -> %d code=%d request_id=%s suppressed=%d: %s\n%s",
+		kind, report.Method, report.Path, report.Status, report.Code, report.RequestID, report.Suppressed, report.Error, report.Stack)
+	return nil
+}
+
+// FileSink 以 JSON Lines 格式追加写入文件的接收端
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink 创建文件接收端，文件不存在时创建
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open error report file %s: %w", path, err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Send 写入一行错误报告
+func (s *FileSink) Send(_ context.Context, report *Report) error {
+	line, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close 关闭文件
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// HTTPSink 以 Sentry 事件格式上报到 HTTP 端点的接收端
+// 可对接 Sentry 的 store 接口，也可指向本地的兼容桩服务
+type HTTPSink struct {
+	URL     string            // 上报地址
+	Headers map[string]string // 附加请求头（如 X-Sentry-Auth）
+	Client  *http.Client
+}
+
+// NewSentrySink 根据 Sentry DSN（https://<key>@<host>/<project>）创建 HTTP 接收端
+func NewSentrySink(dsn string) (*HTTPSink, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse sentry dsn: %w", err)
+	}
+	project := strings.Trim(u.Path, "/")
+	if u.User == nil || u.User.Username() == "" || u.Host == "" || project == "" {
+		return nil, fmt.Errorf("invalid sentry dsn: %s", u.Redacted())
+	}
+
+	auth := "Sentry sentry_version=7, sentry_client=soliton-client/1.0, sentry_key=" + u.User.Username()
+	if secret, ok := u.User.Password(); ok {
+		auth += ", sentry_secret=" + secret
+	}
+	return &HTTPSink{
+		URL:     fmt.Sprintf("%s://%s/api/%s/store/", u.Scheme, u.Host, project),
+		Headers: map[string]string{"X-Sentry-Auth": auth},
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Send 上报错误事件
+func (s *HTTPSink) Send(ctx context.Context, report *Report) error {
+	body, err := json.Marshal(sentryEvent(report))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error report endpoint responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sentryEvent 转换为 Sentry 事件
+func sentryEvent(report *Report) map[string]interface{} {
+	level, kind := "error", "error"
+	if report.Panic {
+		level, kind = "fatal", "panic"
+	}
+	message := report.Message
+	if message == "" {
+		message = report.Error
+	}
+	return map[string]interface{}{
+		"event_id":    newEventID(),
+		"timestamp":   report.Time.UTC().Format(time.RFC3339Nano),
+		"level":       level,
+		"platform":    "go",
+		"logger":      "soliton-client",
+		"transaction": report.Route,
+		"message":     message,
+		"tags": map[string]string{
+			"status":     fmt.Sprint(report.Status),
+			"code":       fmt.Sprint(report.Code),
+			"request_id": report.RequestID,
+		},
+		"request": map[string]string{
+			"method": report.Method,
+			"url":    report.Path,
+		},
+		"exception": map[string]interface{}{
+			"values": []map[string]string{{"type": kind, "value": report.Error}},
+		},
+		"extra": map[string]interface{}{
+			"stack":      report.Stack,
+			"suppressed": report.Suppressed,
+		},
+	}
+}
+
+// newEventID 生成 32 位十六进制事件 ID
+func newEventID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// ReporterOptions 错误上报配置
+type ReporterOptions struct {
+	SampleRate  float64       // 非 panic 错误的采样率（0-1），panic 总是上报
+	DedupWindow time.Duration // 去重窗口，同一指纹在窗口内只上报一次，0 表示不去重
+	Timeout     time.Duration // 单次上报超时
+	QueueSize   int           // 异步上报队列容量，队列已满时丢弃新的报告
+}
+
+// DefaultReporterOptions 默认错误上报配置
+func DefaultReporterOptions() *ReporterOptions {
+	return &ReporterOptions{
+		SampleRate:  1,
+		DedupWindow: time.Minute,
+		Timeout:     5 * time.Second,
+		QueueSize:   defaultQueueSize,
+	}
+}
+
+// defaultQueueSize 默认异步上报队列容量
+const defaultQueueSize = 256
+
+// maxFingerprints 去重记录数量上限，超过时清理过期记录
+const maxFingerprints = 1024
+
+// dedupEntry 去重记录
+type dedupEntry struct {
+	reportedAt time.Time
+	suppressed int
+}
+
+// Reporter 错误上报器，按采样率与指纹去重后发送到各接收端
+// Enqueue 将报告放入有界队列，由单个后台 worker 依次发送，队列已满时丢弃，
+// 避免错误激增时为每个请求创建 goroutine；服务关闭时调用 Close 发送队列中剩余的报告
+type Reporter struct {
+	sinks []Sink
+	opts  *ReporterOptions
+
+	mu     sync.Mutex
+	seen   map[string]*dedupEntry
+	sample func() float64
+
+	queueMu sync.RWMutex
+	queue   chan queuedReport
+	closed  bool
+	done    chan struct{}
+	dropped atomic.Int64
+}
+
+// queuedReport 等待异步发送的报告
+type queuedReport struct {
+	ctx    context.Context
+	report *Report
+}
+
+// NewReporter 创建错误上报器，有接收端时启动后台发送 worker
+func NewReporter(opts *ReporterOptions, sinks ...Sink) *Reporter {
+	if opts == nil {
+		opts = DefaultReporterOptions()
+	}
+	opts.SampleRate = math.Max(0, math.Min(1, opts.SampleRate))
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	r := &Reporter{
+		sinks:  sinks,
+		opts:   opts,
+		seen:   make(map[string]*dedupEntry),
+		sample: mathrand.Float64,
+		queue:  make(chan queuedReport, opts.QueueSize),
+		done:   make(chan struct{}),
+	}
+	if len(sinks) > 0 {
+		go r.run()
+	} else {
+		close(r.done)
+	}
+	return r
+}
+
+// Enqueue 异步上报错误，不阻塞调用方；队列已满或上报器已关闭时丢弃并返回 false
+// ctx 的取消不影响上报，只保留其中的值（如链路信息）
+func (r *Reporter) Enqueue(ctx context.Context, report *Report) bool {
+	if r == nil || len(r.sinks) == 0 {
+		return false
+	}
+
+	r.queueMu.RLock()
+	defer r.queueMu.RUnlock()
+	if r.closed {
+		r.dropped.Add(1)
+		return false
+	}
+	select {
+	case r.queue <- queuedReport{ctx: context.WithoutCancel(ctx), report: report}:
+		return true
+	default:
+		r.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped 因队列已满或上报器已关闭而丢弃的报告数量
+func (r *Reporter) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close 停止接收新的报告并等待队列中的报告发送完成，ctx 结束时不再等待
+func (r *Reporter) Close(ctx context.Context) error {
+	r.queueMu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.queueMu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run 后台 worker，依次发送队列中的报告
+func (r *Reporter) run() {
+	defer close(r.done)
+	for item := range r.queue {
+		r.Report(item.ctx, item.report)
+	}
+}
+
+// Report 同步上报错误，返回是否实际发送（被采样或去重丢弃时返回 false）
+// 各接收端依次发送，单个接收端失败只记录日志
+func (r *Reporter) Report(ctx context.Context, report *Report) bool {
+	if r == nil || len(r.sinks) == 0 {
+		return false
+	}
+	if !report.Panic && r.sample() >= r.opts.SampleRate {
+		return false
+	}
+	if !r.admit(report) {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.opts.Timeout)
+	defer cancel()
+	for _, sink := range r.sinks {
+		if err := sink.Send(ctx, report); err != nil {
+			hlog.CtxWarnf(ctx, "errors: failed to send error report to %T: %v", sink, err)
+		}
+	}
+	return true
+}
+
+// admit 去重判断：窗口内重复的错误只计数，窗口结束后的首次上报附带被丢弃的次数
+func (r *Reporter) admit(report *Report) bool {
+	if r.opts.DedupWindow <= 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	key := report.Fingerprint()
+	if entry, ok := r.seen[key]; ok && now.Sub(entry.reportedAt) < r.opts.DedupWindow {
+		entry.suppressed++
+		return false
+	} else if ok {
+		report.Suppressed = entry.suppressed
+	}
+
+	if _, ok := r.seen[key]; !ok && len(r.seen) >= maxFingerprints {
+		r.evict(now)
+	}
+	r.seen[key] = &dedupEntry{reportedAt: now}
+	return true
+}
+
+// evict 清理过期的去重记录，仍达到上限时淘汰最早上报的记录，保证记录数不超过 maxFingerprints
+func (r *Reporter) evict(now time.Time) {
+	var (
+		oldestKey string
+		oldestAt  time.Time
+	)
+	for k, entry := range r.seen {
+		if now.Sub(entry.reportedAt) >= r.opts.DedupWindow {
+			delete(r.seen, k)
+			continue
+		}
+		if oldestKey == "" || entry.reportedAt.Before(oldestAt) {
+			oldestKey, oldestAt = k, entry.reportedAt
+		}
+	}
+	if len(r.seen) >= maxFingerprints {
+		delete(r.seen, oldestKey)
+	}
+}
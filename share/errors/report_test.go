@@ -0,0 +1,182 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordSink 记录收到的报告，block 非 nil 时发送前等待其关闭
+type recordSink struct {
+	mu      sync.Mutex
+	reports []*Report
+	block   chan struct{}
+}
+
+func (s *recordSink) Send(_ context.Context, report *Report) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reports = append(s.reports, report)
+	return nil
+}
+
+func (s *recordSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.reports)
+}
+
+func TestReporterReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       *ReporterOptions
+		reports    []*Report
+		wantSent   int
+		wantSuppr  int // 最后一次发送的报告附带的去重计数
+		waitWindow bool
+	}{
+		{
+			name:     "dedup within window",
+			opts:     &ReporterOptions{SampleRate: 1, DedupWindow: time.Hour},
+			reports:  []*Report{{Status: 500, Path: "/a"}, {Status: 500, Path: "/a"}, {Status: 500, Path: "/b"}},
+			wantSent: 2,
+		},
+		{
+			name:       "suppressed count after window",
+			opts:       &ReporterOptions{SampleRate: 1, DedupWindow: 20 * time.Millisecond},
+			reports:    []*Report{{Status: 500, Path: "/a"}, {Status: 500, Path: "/a"}, {Status: 500, Path: "/a"}},
+			wantSent:   2,
+			wantSuppr:  1,
+			waitWindow: true,
+		},
+		{
+			name:     "sampled out",
+			opts:     &ReporterOptions{SampleRate: 0},
+			reports:  []*Report{{Status: 500, Path: "/a"}, {Status: 500, Path: "/b"}},
+			wantSent: 0,
+		},
+		{
+			name:     "panic ignores sampling",
+			opts:     &ReporterOptions{SampleRate: 0},
+			reports:  []*Report{{Status: 500, Path: "/a", Panic: true}},
+			wantSent: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			r := NewReporter(tt.opts, sink)
+			defer r.Close(context.Background())
+			for i, report := range tt.reports {
+				// 最后一次上报前等待去重窗口结束
+				if tt.waitWindow && i == len(tt.reports)-1 {
+					time.Sleep(2 * tt.opts.DedupWindow)
+				}
+				r.Report(context.Background(), report)
+			}
+			if got := sink.count(); got != tt.wantSent {
+				t.Fatalf("sent = %d, want %d", got, tt.wantSent)
+			}
+			if tt.wantSent > 0 {
+				if got := sink.reports[len(sink.reports)-1].Suppressed; got != tt.wantSuppr {
+					t.Errorf("suppressed = %d, want %d", got, tt.wantSuppr)
+				}
+			}
+		})
+	}
+}
+
+func TestReporterDedupCap(t *testing.T) {
+	r := NewReporter(&ReporterOptions{SampleRate: 1, DedupWindow: time.Hour}, &recordSink{})
+	defer r.Close(context.Background())
+
+	// 填满去重记录，第 i 条在 i 秒前上报，最后一条最早
+	now := time.Now()
+	for i := 0; i < maxFingerprints; i++ {
+		r.seen[fmt.Sprintf("k%d", i)] = &dedupEntry{reportedAt: now.Add(-time.Duration(i) * time.Second)}
+	}
+	r.seen["expired"] = &dedupEntry{reportedAt: now.Add(-2 * time.Hour)}
+
+	for i := 0; i < 3; i++ {
+		if !r.admit(&Report{Status: 500, Path: fmt.Sprintf("/new/%d", i)}) {
+			t.Fatalf("report %d not admitted", i)
+		}
+		if len(r.seen) > maxFingerprints {
+			t.Fatalf("seen = %d, want at most %d", len(r.seen), maxFingerprints)
+		}
+	}
+	// 先清理过期记录，再按上报时间淘汰最早的记录
+	for _, key := range []string{"expired", fmt.Sprintf("k%d", maxFingerprints-1), fmt.Sprintf("k%d", maxFingerprints-2)} {
+		if _, ok := r.seen[key]; ok {
+			t.Errorf("%s not evicted", key)
+		}
+	}
+	if _, ok := r.seen["k0"]; !ok {
+		t.Error("newest entry evicted")
+	}
+}
+
+func TestReporterQueue(t *testing.T) {
+	sink := &recordSink{block: make(chan struct{})}
+	r := NewReporter(&ReporterOptions{SampleRate: 1, QueueSize: 2}, sink)
+	ctx := context.Background()
+
+	// worker 阻塞在第一个报告上，队列容纳两个，其余丢弃
+	accepted := 0
+	for i := 0; i < 6; i++ {
+		if r.Enqueue(ctx, &Report{Status: 500}) {
+			accepted++
+		}
+		if i == 0 {
+			waitFor(t, func() bool { return len(r.queue) == 0 })
+		}
+	}
+	if accepted != 3 || r.Dropped() != 3 {
+		t.Fatalf("accepted = %d, dropped = %d, want 3 and 3", accepted, r.Dropped())
+	}
+
+	// 关闭时发送队列中剩余的报告，之后不再接收
+	close(sink.block)
+	if err := r.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := sink.count(); got != 3 {
+		t.Fatalf("sent = %d, want 3", got)
+	}
+	if r.Enqueue(ctx, &Report{Status: 500}) || r.Dropped() != 4 {
+		t.Fatalf("enqueue after close accepted, dropped = %d", r.Dropped())
+	}
+	if err := r.Close(ctx); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+}
+
+func TestReporterCloseTimeout(t *testing.T) {
+	sink := &recordSink{block: make(chan struct{})}
+	defer close(sink.block)
+	r := NewReporter(nil, sink)
+	r.Enqueue(context.Background(), &Report{Status: 500})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Close(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("close = %v, want deadline exceeded", err)
+	}
+}
+
+// waitFor 等待条件成立，超时则失败
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
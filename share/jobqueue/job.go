@@ -0,0 +1,52 @@
+// Package jobqueue 基于 PostgreSQL 的持久化任务队列
+// 任务存储在数据库中，服务重启后不会丢失；
+// 通过 SELECT ... FOR UPDATE SKIP LOCKED 保证多个 worker 并发领取任务时互不阻塞
+package jobqueue
+
+import (
+	"context"
+	"time"
+
+	gormrepo "soliton-client/share/repository/gorm"
+)
+
+// JobState 任务状态
+type JobState string
+
+const (
+	StatePending JobState = "pending" // 等待执行（含延迟任务与等待重试的任务）
+	StateRunning JobState = "running" // 执行中
+	StateDone    JobState = "done"    // 执行成功
+	StateDead    JobState = "dead"    // 重试耗尽，进入死信状态
+)
+
+// Job 任务实体
+type Job struct {
+	gormrepo.BaseEntity
+	Kind        string     `gorm:"size:128;not null;index" json:"kind"`                           // 任务类型
+	Payload     string     `gorm:"type:text" json:"payload"`                                      // 任务参数（JSON）
+	State       JobState   `gorm:"size:16;not null;index:idx_jobs_fetch,priority:1" json:"state"` // 任务状态
+	RunAt       time.Time  `gorm:"not null;index:idx_jobs_fetch,priority:2" json:"run_at"`        // 最早执行时间
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`                            // 已尝试次数
+	MaxAttempts int        `gorm:"not null;default:5" json:"max_attempts"`                        // 最大尝试次数
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`                         // 最近一次错误
+	UniqueKey   *string    `gorm:"size:255;uniqueIndex" json:"unique_key,omitempty"`              // 唯一键（未完成任务内唯一，任务结束时释放）
+	DedupKey    string     `gorm:"size:255" json:"dedup_key,omitempty"`                           // 入队时指定的唯一键，任务结束后保留，Retry 时据此恢复 UniqueKey
+	LockedBy    string     `gorm:"size:128" json:"locked_by,omitempty"`                           // 领取任务的 worker
+	LockedAt    *time.Time `json:"locked_at,omitempty"`                                           // 领取时间
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`                                         // 完成时间（成功或死信）
+}
+
+// TableName 表名
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// jobKey 任务上下文键
+type jobKey struct{}
+
+// JobFromContext 在任务处理函数中获取当前任务
+func JobFromContext(ctx context.Context) (*Job, bool) {
+	job, ok := ctx.Value(jobKey{}).(*Job)
+	return job, ok
+}
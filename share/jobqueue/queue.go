@@ -0,0 +1,372 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"soliton-client/share/repository"
+	gormrepo "soliton-client/share/repository/gorm"
+)
+
+var (
+	// ErrDuplicateJob 已存在相同唯一键的未完成任务
+	ErrDuplicateJob = errors.New("job with the same unique key already exists")
+
+	// ErrJobLost 任务已不归当前 worker 所有（领取超时被回收后可能已由其他 worker 领取），执行结果未记录
+	ErrJobLost = errors.New("job is no longer owned by this worker")
+)
+
+// HandlerFunc 任务处理函数，payload 为任务参数的原始 JSON
+type HandlerFunc func(ctx context.Context, payload []byte) error
+
+// BackoffFunc 根据已尝试次数计算下次重试的等待时间
+type BackoffFunc func(attempts int) time.Duration
+
+// DefaultBackoff 默认重试退避：5s 起指数增长，上限 1 小时，附带随机抖动
+func DefaultBackoff(attempts int) time.Duration {
+	d := 5 * time.Second
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// Queue 任务队列
+type Queue struct {
+	repo        *gormrepo.QueryableGormRepository[Job, int]
+	backoff     BackoffFunc
+	maxAttempts int
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// Option 队列选项
+type Option func(q *Queue)
+
+// WithBackoff 设置重试退避策略
+func WithBackoff(backoff BackoffFunc) Option {
+	return func(q *Queue) {
+		q.backoff = backoff
+	}
+}
+
+// WithDefaultMaxAttempts 设置任务默认最大尝试次数
+func WithDefaultMaxAttempts(maxAttempts int) Option {
+	return func(q *Queue) {
+		q.maxAttempts = maxAttempts
+	}
+}
+
+// NewQueue 创建任务队列
+func NewQueue(db *gorm.DB, opts ...Option) *Queue {
+	q := &Queue{
+		repo:        gormrepo.NewQueryableGormRepository[Job, int](db),
+		backoff:     DefaultBackoff,
+		maxAttempts: 5,
+		handlers:    make(map[string]HandlerFunc),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Migrate 创建或更新任务表
+func (q *Queue) Migrate() error {
+	return gormrepo.AutoMigrate(q.repo.DB(), &Job{})
+}
+
+// Handle 注册原始 JSON 任务处理函数
+func (q *Queue) Handle(kind string, handler HandlerFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[kind] = handler
+}
+
+// Register 注册强类型任务处理函数，任务参数自动反序列化为 P
+func Register[P any](q *Queue, kind string, handler func(ctx context.Context, payload P) error) {
+	q.Handle(kind, func(ctx context.Context, raw []byte) error {
+		var payload P
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				return fmt.Errorf("decode payload of job %q: %w", kind, err)
+			}
+		}
+		return handler(ctx, payload)
+	})
+}
+
+// handler 获取任务处理函数
+func (q *Queue) handler(kind string) (HandlerFunc, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	h, ok := q.handlers[kind]
+	return h, ok
+}
+
+// kinds 已注册的任务类型
+func (q *Queue) kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// enqueueOptions 入队选项
+type enqueueOptions struct {
+	runAt       time.Time
+	maxAttempts int
+	uniqueKey   string
+}
+
+// EnqueueOption 入队选项
+type EnqueueOption func(o *enqueueOptions)
+
+// RunAt 指定任务最早执行时间
+func RunAt(t time.Time) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.runAt = t
+	}
+}
+
+// Delay 延迟指定时间后执行
+func Delay(d time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.runAt = time.Now().Add(d)
+	}
+}
+
+// MaxAttempts 指定任务最大尝试次数
+func MaxAttempts(n int) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.maxAttempts = n
+	}
+}
+
+// UniqueKey 指定唯一键，存在相同唯一键的未完成任务时入队返回 ErrDuplicateJob
+func UniqueKey(key string) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.uniqueKey = key
+	}
+}
+
+// Enqueue 任务入队
+// 上下文中存在事务时（如仓储的 WithTx），任务与业务数据在同一事务中提交或回滚
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload interface{}, opts ...EnqueueOption) (*Job, error) {
+	o := &enqueueOptions{
+		runAt:       time.Now(),
+		maxAttempts: q.maxAttempts,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode payload of job %q: %w", kind, err)
+	}
+
+	job := &Job{
+		Kind:        kind,
+		Payload:     string(raw),
+		State:       StatePending,
+		RunAt:       o.runAt,
+		MaxAttempts: o.maxAttempts,
+	}
+	if o.uniqueKey != "" {
+		job.UniqueKey = &o.uniqueKey
+		job.DedupKey = o.uniqueKey
+	}
+
+	// 唯一键冲突时不报错（避免中断 PostgreSQL 上的业务事务），通过影响行数判断
+	result := q.repo.DBContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "unique_key"}}, DoNothing: true}).
+		Create(job)
+	if result.Error != nil {
+		return nil, gormrepo.TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrDuplicateJob
+	}
+	return job, nil
+}
+
+// claim 领取一个可执行的任务并标记为执行中
+func (q *Queue) claim(ctx context.Context, workerID string) (*Job, error) {
+	kinds := q.kinds()
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+
+	var claimed *Job
+	err := q.repo.WithTx(ctx, func(ctx context.Context) error {
+		job, err := q.repo.Query().
+			Where(repository.Eq("state", StatePending)).
+			And(repository.Lte("run_at", time.Now()), repository.In("kind", kinds)).
+			OrderBy("run_at").
+			ForUpdate().
+			SkipLocked().
+			First(ctx)
+		if err != nil || job == nil {
+			return err
+		}
+
+		now := time.Now()
+		job.State = StateRunning
+		job.Attempts++
+		job.LockedBy = workerID
+		job.LockedAt = &now
+		if err := q.repo.Update(ctx, job); err != nil {
+			return err
+		}
+		claimed = job
+		return nil
+	})
+	return claimed, err
+}
+
+// complete 标记任务成功，并释放唯一键以允许再次入队
+func (q *Queue) complete(ctx context.Context, job *Job) error {
+	now := time.Now()
+	if err := q.release(ctx, job, map[string]interface{}{
+		"state":       StateDone,
+		"finished_at": now,
+		"last_error":  "",
+		"unique_key":  nil,
+	}); err != nil {
+		return err
+	}
+	job.State = StateDone
+	job.FinishedAt = &now
+	job.LastError = ""
+	job.UniqueKey = nil
+	return nil
+}
+
+// fail 记录任务失败，未达到最大尝试次数时按退避策略重新排队，否则进入死信状态
+func (q *Queue) fail(ctx context.Context, job *Job, cause error) error {
+	updates := map[string]interface{}{
+		"last_error": cause.Error(),
+		"locked_by":  "",
+		"locked_at":  nil,
+	}
+	now := time.Now()
+	dead := job.Attempts >= job.MaxAttempts
+	var runAt time.Time
+	if dead {
+		updates["state"] = StateDead
+		updates["finished_at"] = now
+		updates["unique_key"] = nil
+	} else {
+		runAt = now.Add(q.backoff(job.Attempts))
+		updates["state"] = StatePending
+		updates["run_at"] = runAt
+	}
+	if err := q.release(ctx, job, updates); err != nil {
+		return err
+	}
+
+	job.LastError = cause.Error()
+	job.LockedBy = ""
+	job.LockedAt = nil
+	if dead {
+		job.State = StateDead
+		job.FinishedAt = &now
+		job.UniqueKey = nil
+	} else {
+		job.State = StatePending
+		job.RunAt = runAt
+	}
+	return nil
+}
+
+// release 记录执行结果，仅当任务仍由当前 worker 以本次尝试持有时更新
+// 任务领取超时被回收后，原 worker 的结果不得覆盖新领取者的状态，此时返回 ErrJobLost
+func (q *Queue) release(ctx context.Context, job *Job, updates map[string]interface{}) error {
+	result := q.repo.DBContext(ctx).
+		Model(&Job{}).
+		Where("id = ? AND state = ? AND locked_by = ? AND attempts = ?", job.ID, StateRunning, job.LockedBy, job.Attempts).
+		Updates(updates)
+	if result.Error != nil {
+		return gormrepo.TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobLost
+	}
+	return nil
+}
+
+// rescue 将领取超时（worker 崩溃或被强制终止）的任务重新置为待执行
+func (q *Queue) rescue(ctx context.Context, lockTimeout time.Duration) (int64, error) {
+	result := q.repo.DBContext(ctx).
+		Model(&Job{}).
+		Where("state = ? AND locked_at < ?", StateRunning, time.Now().Add(-lockTimeout)).
+		Updates(map[string]interface{}{
+			"state":     StatePending,
+			"locked_by": "",
+			"locked_at": nil,
+		})
+	return result.RowsAffected, gormrepo.TranslateError(result.Error)
+}
+
+// Retry 将死信任务重新置为待执行，并恢复入队时指定的唯一键
+// 已存在相同唯一键的未完成任务时返回 ErrDuplicateJob，任务保持死信状态
+func (q *Queue) Retry(ctx context.Context, id int) error {
+	job, err := q.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job == nil || job.State != StateDead {
+		return fmt.Errorf("job %d is not in dead state", id)
+	}
+
+	var uniqueKey *string
+	if job.DedupKey != "" {
+		uniqueKey = &job.DedupKey
+	}
+	result := q.repo.DBContext(ctx).
+		Model(&Job{}).
+		Where("id = ? AND state = ?", id, StateDead).
+		Updates(map[string]interface{}{
+			"state":       StatePending,
+			"attempts":    0,
+			"run_at":      time.Now(),
+			"finished_at": nil,
+			"unique_key":  uniqueKey,
+		})
+	if result.Error != nil {
+		err := gormrepo.TranslateError(result.Error)
+		var constraintErr *gormrepo.ConstraintError
+		if errors.As(err, &constraintErr) && constraintErr.Kind == gormrepo.ConstraintUnique {
+			return ErrDuplicateJob
+		}
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("job %d is not in dead state", id)
+	}
+	return nil
+}
+
+// DeadJobs 分页查询死信任务
+func (q *Queue) DeadJobs(ctx context.Context, page, size int) (*repository.PageResult[*Job], error) {
+	request := repository.NewPageRequest(page, size).
+		WithCondition(repository.Eq("state", StateDead)).
+		WithOrderBy("finished_at", true)
+	return q.repo.Page(ctx, request)
+}
@@ -0,0 +1,232 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestQueue 创建基于内存 SQLite 的队列
+// SQLite 不支持 SKIP LOCKED，测试中通过 claimAs 模拟领取
+func newTestQueue(t *testing.T) *Queue {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	q := NewQueue(db, WithBackoff(func(int) time.Duration { return time.Minute }))
+	if err := q.Migrate(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return q
+}
+
+// claimAs 模拟 worker 领取任务
+func claimAs(t *testing.T, q *Queue, job *Job, workerID string) *Job {
+	t.Helper()
+	now := time.Now()
+	err := q.repo.DB().Model(&Job{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"state":     StateRunning,
+		"attempts":  gorm.Expr("attempts + 1"),
+		"locked_by": workerID,
+		"locked_at": now,
+	}).Error
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	return reload(t, q, job.ID)
+}
+
+// reload 重新读取任务
+func reload(t *testing.T, q *Queue, id int) *Job {
+	t.Helper()
+	job, err := q.repo.GetByID(context.Background(), id)
+	if err != nil || job == nil {
+		t.Fatalf("get job %d: %v", id, err)
+	}
+	return job
+}
+
+func TestEnqueueUniqueKey(t *testing.T) {
+	q := newTestQueue(t)
+	ctx := context.Background()
+
+	first, err := q.Enqueue(ctx, "email", map[string]string{"to": "a"}, UniqueKey("k1"))
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if first.DedupKey != "k1" {
+		t.Errorf("dedup key = %q, want k1", first.DedupKey)
+	}
+	if _, err := q.Enqueue(ctx, "email", nil, UniqueKey("k1")); !errors.Is(err, ErrDuplicateJob) {
+		t.Fatalf("duplicate enqueue err = %v, want ErrDuplicateJob", err)
+	}
+
+	// 完成后释放唯一键，允许再次入队
+	if err := q.complete(ctx, claimAs(t, q, first, "w1")); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if _, err := q.Enqueue(ctx, "email", nil, UniqueKey("k1")); err != nil {
+		t.Fatalf("enqueue after complete: %v", err)
+	}
+}
+
+func TestFail(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		wantState   JobState
+	}{
+		{name: "reschedule", maxAttempts: 3, wantState: StatePending},
+		{name: "dead letter", maxAttempts: 1, wantState: StateDead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newTestQueue(t)
+			ctx := context.Background()
+			job, err := q.Enqueue(ctx, "email", nil, MaxAttempts(tt.maxAttempts), UniqueKey("k"))
+			if err != nil {
+				t.Fatalf("enqueue: %v", err)
+			}
+
+			if err := q.fail(ctx, claimAs(t, q, job, "w1"), errors.New("smtp down")); err != nil {
+				t.Fatalf("fail: %v", err)
+			}
+			got := reload(t, q, job.ID)
+			if got.State != tt.wantState || got.LastError != "smtp down" || got.LockedBy != "" {
+				t.Fatalf("job = %+v", got)
+			}
+			switch tt.wantState {
+			case StatePending:
+				if !got.RunAt.After(time.Now().Add(30*time.Second)) || got.UniqueKey == nil {
+					t.Errorf("run_at = %v, unique_key = %v, want backoff and key kept", got.RunAt, got.UniqueKey)
+				}
+			case StateDead:
+				if got.FinishedAt == nil || got.UniqueKey != nil || got.DedupKey != "k" {
+					t.Errorf("finished_at = %v, unique_key = %v, dedup_key = %q", got.FinishedAt, got.UniqueKey, got.DedupKey)
+				}
+			}
+		})
+	}
+}
+
+func TestStaleWorkerCannotOverwrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(q *Queue, job *Job) error
+	}{
+		{name: "complete", record: func(q *Queue, job *Job) error { return q.complete(context.Background(), job) }},
+		{name: "fail", record: func(q *Queue, job *Job) error {
+			return q.fail(context.Background(), job, errors.New("late failure"))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newTestQueue(t)
+			ctx := context.Background()
+			job, err := q.Enqueue(ctx, "email", nil)
+			if err != nil {
+				t.Fatalf("enqueue: %v", err)
+			}
+			stale := claimAs(t, q, job, "w1")
+
+			// 领取超时被回收，并由另一个 worker 重新领取
+			if n, err := q.rescue(ctx, -time.Minute); err != nil || n != 1 {
+				t.Fatalf("rescue = %d, %v", n, err)
+			}
+			claimAs(t, q, job, "w2")
+
+			if err := tt.record(q, stale); !errors.Is(err, ErrJobLost) {
+				t.Fatalf("stale %s err = %v, want ErrJobLost", tt.name, err)
+			}
+			got := reload(t, q, job.ID)
+			if got.State != StateRunning || got.LockedBy != "w2" || got.Attempts != 2 {
+				t.Fatalf("job overwritten by stale worker: %+v", got)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	q := newTestQueue(t)
+	ctx := context.Background()
+
+	dead, err := q.Enqueue(ctx, "email", nil, MaxAttempts(1), UniqueKey("k"))
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if err := q.fail(ctx, claimAs(t, q, dead, "w1"), errors.New("boom")); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+
+	// 死信任务释放了唯一键，此时可以入队相同唯一键的任务
+	active, err := q.Enqueue(ctx, "email", nil, UniqueKey("k"))
+	if err != nil {
+		t.Fatalf("enqueue active: %v", err)
+	}
+	if err := q.Retry(ctx, dead.ID); !errors.Is(err, ErrDuplicateJob) {
+		t.Fatalf("retry with active duplicate err = %v, want ErrDuplicateJob", err)
+	}
+	if got := reload(t, q, dead.ID); got.State != StateDead {
+		t.Fatalf("state = %s, want dead", got.State)
+	}
+
+	// 相同唯一键的任务完成后，Retry 恢复唯一键
+	if err := q.complete(ctx, claimAs(t, q, active, "w2")); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if err := q.Retry(ctx, dead.ID); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	got := reload(t, q, dead.ID)
+	if got.State != StatePending || got.Attempts != 0 || got.UniqueKey == nil || *got.UniqueKey != "k" {
+		t.Fatalf("retried job = %+v", got)
+	}
+	if _, err := q.Enqueue(ctx, "email", nil, UniqueKey("k")); !errors.Is(err, ErrDuplicateJob) {
+		t.Fatalf("enqueue after retry err = %v, want ErrDuplicateJob", err)
+	}
+	if err := q.Retry(ctx, dead.ID); err == nil {
+		t.Fatal("retry of pending job should fail")
+	}
+}
+
+func TestRunRecordsResultAfterStop(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   HandlerFunc
+		wantState JobState
+	}{
+		{name: "success", handler: func(context.Context, []byte) error { return nil }, wantState: StateDone},
+		{name: "failure", handler: func(context.Context, []byte) error { return errors.New("boom") }, wantState: StatePending},
+		{name: "panic", handler: func(context.Context, []byte) error { panic("boom") }, wantState: StatePending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newTestQueue(t)
+			q.Handle("email", tt.handler)
+			job, err := q.Enqueue(context.Background(), "email", nil)
+			if err != nil {
+				t.Fatalf("enqueue: %v", err)
+			}
+
+			// worker 池已因停止超时取消执行上下文
+			p := NewWorkerPool(q, nil)
+			p.runCtx, p.cancelRun = context.WithCancel(context.Background())
+			p.cancelRun()
+
+			p.run(claimAs(t, q, job, "w1"))
+			if got := reload(t, q, job.ID); got.State != tt.wantState {
+				t.Fatalf("state = %s, want %s", got.State, tt.wantState)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package jobqueue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+)
+
+// resultTimeout 记录任务执行结果的超时时间
+// 结果写入不随 worker 池停止而取消，避免已完成的任务因无法记录结果而被回收后重复执行
+const resultTimeout = 10 * time.Second
+
+// WorkerOptions worker 池配置
+type WorkerOptions struct {
+	Concurrency  int           // 并发 worker 数量
+	PollInterval time.Duration // 队列为空时的轮询间隔
+	LockTimeout  time.Duration // 任务领取超时，超过后视为 worker 已崩溃并重新排队
+	WorkerID     string        // worker 标识前缀，默认为主机名
+}
+
+// DefaultWorkerOptions 默认 worker 池配置
+func DefaultWorkerOptions() *WorkerOptions {
+	hostname, _ := os.Hostname()
+	return &WorkerOptions{
+		Concurrency:  4,
+		PollInterval: time.Second,
+		LockTimeout:  15 * time.Minute,
+		WorkerID:     hostname,
+	}
+}
+
+// WorkerPool 任务 worker 池
+type WorkerPool struct {
+	queue *Queue
+	opts  *WorkerOptions
+
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+}
+
+// NewWorkerPool 创建 worker 池
+func NewWorkerPool(queue *Queue, opts *WorkerOptions) *WorkerPool {
+	if opts == nil {
+		opts = DefaultWorkerOptions()
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = 15 * time.Minute
+	}
+	return &WorkerPool{
+		queue:  queue,
+		opts:   opts,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start 启动 worker 池（非阻塞）
+func (p *WorkerPool) Start(ctx context.Context) {
+	p.runCtx, p.cancelRun = context.WithCancel(context.WithoutCancel(ctx))
+
+	p.wg.Add(1)
+	go p.rescueLoop()
+
+	for i := 0; i < p.opts.Concurrency; i++ {
+		p.wg.Add(1)
+		go p.work(fmt.Sprintf("%s-%d", p.opts.WorkerID, i))
+	}
+}
+
+// Stop 优雅停止：不再领取新任务，等待执行中的任务完成
+// ctx 到期时取消执行中任务的上下文并返回，未完成的任务在领取超时后会被重新排队
+func (p *WorkerPool) Stop(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancelRun()
+		return nil
+	case <-ctx.Done():
+		p.cancelRun()
+		return ctx.Err()
+	}
+}
+
+// work 单个 worker 循环
+func (p *WorkerPool) work(workerID string) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		default:
+		}
+
+		job, err := p.queue.claim(p.runCtx, workerID)
+		if err != nil {
+			hlog.CtxErrorf(p.runCtx, "jobqueue: worker %s failed to claim job: %v", workerID, err)
+		}
+		if job == nil {
+			if !p.wait(p.opts.PollInterval) {
+				return
+			}
+			continue
+		}
+
+		p.run(job)
+	}
+}
+
+// run 执行任务并记录结果
+func (p *WorkerPool) run(job *Job) {
+	ctx := context.WithValue(p.runCtx, jobKey{}, job)
+
+	err := p.execute(ctx, job)
+
+	resultCtx, cancel := context.WithTimeout(context.WithoutCancel(p.runCtx), resultTimeout)
+	defer cancel()
+	if err == nil {
+		if err := p.queue.complete(resultCtx, job); err != nil {
+			hlog.CtxErrorf(ctx, "jobqueue: failed to mark job %d as done: %v", job.ID, err)
+		}
+		return
+	}
+
+	hlog.CtxWarnf(ctx, "jobqueue: job %d (%s) attempt %d/%d failed: %v", job.ID, job.Kind, job.Attempts, job.MaxAttempts, err)
+	if failErr := p.queue.fail(resultCtx, job, err); failErr != nil {
+		hlog.CtxErrorf(ctx, "jobqueue: failed to record failure of job %d: %v", job.ID, failErr)
+	}
+}
+
+// execute 调用任务处理函数，panic 视为执行失败
+func (p *WorkerPool) execute(ctx context.Context, job *Job) (err error) {
+	handler, ok := p.queue.handler(job.Kind)
+	if !ok {
+		return fmt.Errorf("no handler registered for job kind %q", job.Kind)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return handler(ctx, []byte(job.Payload))
+}
+
+// rescueLoop 定期回收领取超时的任务
+func (p *WorkerPool) rescueLoop() {
+	defer p.wg.Done()
+
+	interval := p.opts.LockTimeout / 2
+	for {
+		if n, err := p.queue.rescue(p.runCtx, p.opts.LockTimeout); err != nil {
+			hlog.CtxErrorf(p.runCtx, "jobqueue: failed to rescue stale jobs: %v", err)
+		} else if n > 0 {
+			hlog.CtxWarnf(p.runCtx, "jobqueue: rescued %d stale jobs", n)
+		}
+		if !p.wait(interval) {
+			return
+		}
+	}
+}
+
+// wait 等待指定时间，收到停止信号时返回 false
+func (p *WorkerPool) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-p.stopCh:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
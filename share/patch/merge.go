@@ -0,0 +1,130 @@
+// Package patch 提供与存储无关的 JSON Merge Patch（RFC 7396）实现
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// ApplyMerge 将 JSON Merge Patch 应用到实体
+// 补丁只能包含 allowed 中的顶层 JSON 字段（allowed 为空时拒绝任何字段），
+// 值为 null 表示重置为零值；嵌套对象按 RFC 7396 合并。
+// 返回值为发生变化的字段（结构体字段名 -> 新值），可直接传给仓储的 UpdateFields
+func ApplyMerge[T any](entity *T, doc []byte, allowed ...string) (map[string]interface{}, error) {
+	var patchDoc map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &patchDoc); err != nil {
+		return nil, apperrors.Wrap(apperrors.BadRequest, "补丁格式错误，必须为 JSON 对象", err)
+	}
+
+	allowedSet := make(map[string]struct{}, len(allowed))
+	for _, name := range allowed {
+		allowedSet[name] = struct{}{}
+	}
+
+	current, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var currentDoc map[string]interface{}
+	if err := json.Unmarshal(current, &currentDoc); err != nil {
+		return nil, err
+	}
+
+	rv := reflect.ValueOf(entity).Elem()
+	fields := jsonFields(rv.Type())
+	changes := make(map[string]interface{})
+
+	for name, rawPatch := range patchDoc {
+		if _, ok := allowedSet[name]; !ok {
+			return nil, apperrors.ErrBadRequest(fmt.Sprintf("字段 %s 不允许修改", name))
+		}
+		index, ok := fields[name]
+		if !ok {
+			return nil, apperrors.ErrBadRequest(fmt.Sprintf("未知字段: %s", name))
+		}
+
+		var patchValue interface{}
+		if err := json.Unmarshal(rawPatch, &patchValue); err != nil {
+			return nil, apperrors.Wrap(apperrors.BadRequest, "补丁格式错误", err)
+		}
+
+		// 计算合并后的字段值，并解码为字段的实际类型
+		fieldValue := rv.FieldByIndex(index)
+		next := reflect.New(fieldValue.Type())
+		if merged, keep := mergeValue(currentDoc[name], patchValue); keep {
+			raw, err := json.Marshal(merged)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(raw, next.Interface()); err != nil {
+				return nil, apperrors.Wrap(apperrors.BadRequest, fmt.Sprintf("字段 %s 类型错误", name), err)
+			}
+		}
+
+		if reflect.DeepEqual(fieldValue.Interface(), next.Elem().Interface()) {
+			continue
+		}
+		fieldValue.Set(next.Elem())
+		changes[rv.Type().FieldByIndex(index).Name] = next.Elem().Interface()
+	}
+	return changes, nil
+}
+
+// mergeValue 按 RFC 7396 合并单个值，返回合并结果以及该值是否保留（null 表示删除）
+func mergeValue(target, patch interface{}) (interface{}, bool) {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch, patch != nil
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{})
+	}
+	result := make(map[string]interface{}, len(targetObj))
+	for k, v := range targetObj {
+		result[k] = v
+	}
+	for k, v := range patchObj {
+		if merged, keep := mergeValue(result[k], v); keep {
+			result[k] = merged
+		} else {
+			delete(result, k)
+		}
+	}
+	return result, true
+}
+
+// jsonFields 构建 JSON 字段名到结构体字段索引的映射（展开匿名嵌入结构体）
+func jsonFields(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for embedded, index := range jsonFields(f.Type) {
+				if _, exists := fields[embedded]; !exists {
+					fields[embedded] = append([]int{i}, index...)
+				}
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = []int{i}
+	}
+	return fields
+}
@@ -0,0 +1,74 @@
+package patch
+
+import (
+	"reflect"
+	"testing"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// Address 测试用嵌入结构体
+type Address struct {
+	City string `json:"city"`
+}
+
+// profile 测试用实体
+type profile struct {
+	Address
+	Name  string            `json:"name"`
+	Age   int               `json:"age,omitempty"`
+	Tags  map[string]string `json:"tags"`
+	Note  string            `json:"-"`
+	Owner string
+}
+
+func TestApplyMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		allowed  []string
+		want     map[string]interface{}
+		wantCode int // 期望的错误码，0 表示成功
+	}{
+		{name: "changed only", doc: `{"name":"bob","age":3}`, allowed: []string{"name", "age"}, want: map[string]interface{}{"Name": "bob"}},
+		{name: "null resets", doc: `{"age":null}`, allowed: []string{"age"}, want: map[string]interface{}{"Age": 0}},
+		{
+			name:    "nested object merged",
+			doc:     `{"tags":{"a":null,"c":"3"}}`,
+			allowed: []string{"tags"},
+			want:    map[string]interface{}{"Tags": map[string]string{"b": "2", "c": "3"}},
+		},
+		{name: "embedded field", doc: `{"city":"sh"}`, allowed: []string{"city"}, want: map[string]interface{}{"City": "sh"}},
+		{name: "field without tag", doc: `{"Owner":"x"}`, allowed: []string{"Owner"}, want: map[string]interface{}{"Owner": "x"}},
+		{name: "empty patch", doc: `{}`, want: map[string]interface{}{}},
+		{name: "not allowed", doc: `{"name":"bob"}`, wantCode: apperrors.BadRequest},
+		{name: "ignored field unknown", doc: `{"-":"x","Note":"x"}`, allowed: []string{"Note"}, wantCode: apperrors.BadRequest},
+		{name: "wrong type", doc: `{"age":"x"}`, allowed: []string{"age"}, wantCode: apperrors.BadRequest},
+		{name: "not an object", doc: `[1]`, wantCode: apperrors.BadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity := &profile{Address: Address{City: "bj"}, Name: "alice", Age: 3, Tags: map[string]string{"a": "1", "b": "2"}}
+			changes, err := ApplyMerge(entity, []byte(tt.doc), tt.allowed...)
+			if tt.wantCode != 0 {
+				if appErr, ok := apperrors.AsAppError(err); !ok || appErr.Code != tt.wantCode {
+					t.Fatalf("err = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !reflect.DeepEqual(changes, tt.want) {
+				t.Errorf("changes = %#v, want %#v", changes, tt.want)
+			}
+			// 实体已应用补丁
+			for name, value := range changes {
+				got := reflect.ValueOf(entity).Elem().FieldByName(name).Interface()
+				if !reflect.DeepEqual(got, value) {
+					t.Errorf("%s = %#v, want %#v", name, got, value)
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 )
 
 // QueryBuilder 查询构建器接口，提供链式调用的查询构建能力
+// 构建器是不可变的：每次链式调用返回新的构建器，原构建器保持不变，
+// 因此同一个构建器可以复用（如查询多页）或在多个 goroutine 间共享
 type QueryBuilder[T any] interface {
 	// Where 添加查询条件
 	Where(condition *Condition) QueryBuilder[T]
@@ -18,6 +20,9 @@ type QueryBuilder[T any] interface {
 	// OrderByDesc 添加排序（降序）
 	OrderByDesc(field string) QueryBuilder[T]
 
+	// OrderByRelevance 按全文检索条件的相关度降序排序
+	OrderByRelevance() QueryBuilder[T]
+
 	// Limit 限制返回数量
 	Limit(limit int) QueryBuilder[T]
 
@@ -27,6 +32,18 @@ type QueryBuilder[T any] interface {
 	// Select 指定查询字段
 	Select(fields ...string) QueryBuilder[T]
 
+	// ForUpdate 加排他行锁（SELECT ... FOR UPDATE），必须在事务中使用
+	ForUpdate() QueryBuilder[T]
+
+	// ForShare 加共享行锁（SELECT ... FOR SHARE），必须在事务中使用
+	ForShare() QueryBuilder[T]
+
+	// NoWait 行锁被占用时立即报错而不是等待
+	NoWait() QueryBuilder[T]
+
+	// SkipLocked 跳过已被锁定的行
+	SkipLocked() QueryBuilder[T]
+
 	// Find 执行查询，返回结果列表
 	Find(ctx context.Context) ([]*T, error)
 
@@ -34,10 +51,20 @@ type QueryBuilder[T any] interface {
 	First(ctx context.Context) (*T, error)
 
 	// Count 执行统计查询
+	// 只应用查询条件，忽略排序、分页、字段选择与行锁
 	Count(ctx context.Context) (int64, error)
 
 	// Exists 执行存在性检查
 	Exists(ctx context.Context) (bool, error)
+
+	// Clone 复制构建器
+	Clone() QueryBuilder[T]
+
+	// ToSQL 生成查询语句（参数已内联），用于调试与测试断言，不访问数据库
+	ToSQL() (string, error)
+
+	// Explain 返回数据库的执行计划
+	Explain(ctx context.Context) (string, error)
 }
 
 // QueryOptions 查询选项，用于存储构建器的状态
@@ -47,8 +74,28 @@ type QueryOptions struct {
 	LimitVal   int          // 限制数量
 	OffsetVal  int          // 偏移量
 	Fields     []string     // 查询字段
+	Lock       LockStrength // 行锁强度
+	LockWait   LockWait     // 行锁等待策略
 }
 
+// LockStrength 行锁强度
+type LockStrength string
+
+const (
+	LockNone      LockStrength = ""       // 不加锁
+	LockForUpdate LockStrength = "UPDATE" // 排他锁
+	LockForShare  LockStrength = "SHARE"  // 共享锁
+)
+
+// LockWait 行锁等待策略
+type LockWait string
+
+const (
+	LockWaitDefault LockWait = ""            // 阻塞等待
+	LockNoWait      LockWait = "NOWAIT"      // 不等待，立即报错
+	LockSkipLocked  LockWait = "SKIP LOCKED" // 跳过已锁定的行
+)
+
 // NewQueryOptions 创建查询选项
 func NewQueryOptions() *QueryOptions {
 	return &QueryOptions{
@@ -60,6 +107,15 @@ func NewQueryOptions() *QueryOptions {
 	}
 }
 
+// Clone 复制查询选项，副本与原对象互不影响
+func (o *QueryOptions) Clone() *QueryOptions {
+	clone := *o
+	clone.Conditions = append(make([]*Condition, 0, len(o.Conditions)), o.Conditions...)
+	clone.OrderBys = append(make([]OrderBy, 0, len(o.OrderBys)), o.OrderBys...)
+	clone.Fields = append(make([]string, 0, len(o.Fields)), o.Fields...)
+	return &clone
+}
+
 // AddCondition 添加条件
 func (o *QueryOptions) AddCondition(condition *Condition) *QueryOptions {
 	o.Conditions = append(o.Conditions, condition)
@@ -95,3 +151,15 @@ func (o *QueryOptions) SetFields(fields ...string) *QueryOptions {
 	o.Fields = fields
 	return o
 }
+
+// SetLock 设置行锁强度
+func (o *QueryOptions) SetLock(strength LockStrength) *QueryOptions {
+	o.Lock = strength
+	return o
+}
+
+// SetLockWait 设置行锁等待策略
+func (o *QueryOptions) SetLockWait(wait LockWait) *QueryOptions {
+	o.LockWait = wait
+	return o
+}
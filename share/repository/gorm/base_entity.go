@@ -84,6 +84,56 @@ func (e *AuditFields) Touch() {
 	e.UpdatedAt = time.Now()
 }
 
+// UUIDEntity 使用 UUIDv7 主键的基础实体
+// 主键在插入前由回调生成，可安全暴露在 URL 中
+type UUIDEntity struct {
+	ID string `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	AuditFields
+}
+
+// GetID 获取实体主键
+func (e *UUIDEntity) GetID() string {
+	return e.ID
+}
+
+// IDStrategy 主键生成策略
+func (e *UUIDEntity) IDStrategy() IDStrategy {
+	return IDUUIDv7
+}
+
+// ULIDEntity 使用 ULID 主键的基础实体
+type ULIDEntity struct {
+	ID string `gorm:"primaryKey;type:varchar(26)" json:"id"`
+	AuditFields
+}
+
+// GetID 获取实体主键
+func (e *ULIDEntity) GetID() string {
+	return e.ID
+}
+
+// IDStrategy 主键生成策略
+func (e *ULIDEntity) IDStrategy() IDStrategy {
+	return IDULID
+}
+
+// SnowflakeEntity 使用 Snowflake 主键的基础实体
+// JSON 序列化为字符串，避免前端 JavaScript 丢失 64 位整数精度
+type SnowflakeEntity struct {
+	ID int64 `gorm:"primaryKey;autoIncrement:false" json:"id,string"`
+	AuditFields
+}
+
+// GetID 获取实体主键
+func (e *SnowflakeEntity) GetID() int64 {
+	return e.ID
+}
+
+// IDStrategy 主键生成策略
+func (e *SnowflakeEntity) IDStrategy() IDStrategy {
+	return IDSnowflake
+}
+
 // Auditable 可审计接口，实现此接口的实体将自动填充审计字段
 type Auditable interface {
 	SetCreatedAt(t time.Time)
@@ -0,0 +1,259 @@
+package gorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm/schema"
+
+	"soliton-client/share/validation"
+)
+
+// DefaultBatchSize 默认每批写入数量
+// PostgreSQL 单条语句最多 65535 个参数，按 20 列估算留有余量
+const DefaultBatchSize = 1000
+
+// ErrCopyNotSupported 当前连接不支持 COPY FROM
+var ErrCopyNotSupported = errors.New("copy from is only supported on postgres pgx connections")
+
+// ErrCopyDefaultValue 由数据库生成默认值的字段在部分实体中为零值，COPY 无法按行使用默认值
+var ErrCopyDefaultValue = errors.New("copy from cannot mix zero and non-zero values of a field with a database default")
+
+// BatchProgress 批量写入进度
+type BatchProgress struct {
+	Chunk   int // 当前批次序号（从 1 开始）
+	Chunks  int // 总批次数
+	Rows    int // 当前批次写入行数
+	Written int // 累计写入行数
+	Total   int // 总行数
+}
+
+// BatchOptions 批量写入选项
+type BatchOptions struct {
+	// Size 每批数量，小于等于 0 时使用 DefaultBatchSize
+	Size int
+
+	// UseCopy 使用 COPY FROM 写入，仅在 PostgreSQL 且上下文中没有事务时生效，
+	// 其他情况（非 PostgreSQL、上下文中已有事务）回退为 INSERT，不返回错误
+	UseCopy bool
+
+	// OnProgress 每批写入完成后回调
+	// 回调发生在事务提交之前：此时数据对其他连接尚不可见，后续批次失败时已回调的批次也会回滚；
+	// 加入外层事务时，数据在外层事务提交后才生效
+	OnProgress func(progress BatchProgress)
+}
+
+// DefaultBatchOptions 默认批量写入选项
+func DefaultBatchOptions() *BatchOptions {
+	return &BatchOptions{Size: DefaultBatchSize}
+}
+
+// CreateBatchWithOptions 分批创建实体
+// 所有批次在同一事务中写入（上下文中存在事务时加入该事务），任一批次失败则整体回滚。
+// INSERT 方式由数据库回填自增主键；COPY 方式仅在 PostgreSQL 且上下文中没有事务时生效
+// （COPY 无法加入 database/sql 事务，此时回退为 INSERT），不回填自增主键，
+// 审计字段、主键生成策略、default 标签默认值与校验在写入前由仓储完成，GORM 钩子方法不会被调用
+func (r *GormRepository[T, ID]) CreateBatchWithOptions(ctx context.Context, entities []*T, opts *BatchOptions) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	if opts == nil {
+		opts = DefaultBatchOptions()
+	}
+	size := opts.Size
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	_, inTx := txFromContext(ctx)
+	if opts.UseCopy && !inTx && dialectOf(r.db) == PostgreSQL {
+		return r.copyBatch(ctx, entities, size, opts.OnProgress)
+	}
+
+	// 批次失败会整体回滚，不自动重试，避免重复回调进度
+	return r.WithTxOptions(ctx, &TxOptions{MaxAttempts: 1}, func(ctx context.Context) error {
+		return eachChunk(entities, size, opts.OnProgress, func(chunk []*T) error {
+			return TranslateError(r.getDB(ctx).Create(chunk).Error)
+		})
+	})
+}
+
+// eachChunk 按批次执行写入并回调进度
+func eachChunk[T any](entities []*T, size int, onProgress func(BatchProgress), write func(chunk []*T) error) error {
+	chunks := (len(entities) + size - 1) / size
+	written := 0
+	for i := 0; i < chunks; i++ {
+		end := (i + 1) * size
+		if end > len(entities) {
+			end = len(entities)
+		}
+		chunk := entities[i*size : end]
+		if err := write(chunk); err != nil {
+			return err
+		}
+
+		written += len(chunk)
+		if onProgress != nil {
+			onProgress(BatchProgress{
+				Chunk:   i + 1,
+				Chunks:  chunks,
+				Rows:    len(chunk),
+				Written: written,
+				Total:   len(entities),
+			})
+		}
+	}
+	return nil
+}
+
+// copyBatch 使用 PostgreSQL COPY FROM 分批写入
+func (r *GormRepository[T, ID]) copyBatch(ctx context.Context, entities []*T, size int, onProgress func(BatchProgress)) error {
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+	if err := r.prepareCopy(ctx, s, entities); err != nil {
+		return err
+	}
+	columns, err := copyColumns(ctx, s, entities)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(columns))
+	for i, field := range columns {
+		names[i] = field.DBName
+	}
+	table := pgx.Identifier(strings.Split(s.Table, "."))
+
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return ErrCopyNotSupported
+		}
+		return pgx.BeginFunc(ctx, stdConn.Conn(), func(tx pgx.Tx) error {
+			return eachChunk(entities, size, onProgress, func(chunk []*T) error {
+				rows, err := copyRows(ctx, columns, chunk)
+				if err != nil {
+					return err
+				}
+				_, err = tx.CopyFrom(ctx, table, names, pgx.CopyFromRows(rows))
+				return err
+			})
+		})
+	})
+	return TranslateError(err)
+}
+
+// prepareCopy 写入前填充审计字段与默认值、分配主键并校验实体
+func (r *GormRepository[T, ID]) prepareCopy(ctx context.Context, s *schema.Schema, entities []*T) error {
+	generators, _ := idGeneratorsOf(r.db)
+	now := time.Now()
+	for _, entity := range entities {
+		rv := reflect.ValueOf(entity).Elem()
+		fillCreateAudit(ctx, s, rv, now)
+		if err := fillDefaults(ctx, s, rv); err != nil {
+			return err
+		}
+		if generators != nil {
+			if err := generators.Assign(ctx, s, rv); err != nil {
+				return err
+			}
+		}
+		if err := validation.Struct(ctx, entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillDefaults 与 INSERT 一致，为零值字段填充 default 标签中的常量默认值
+func fillDefaults(ctx context.Context, s *schema.Schema, rv reflect.Value) error {
+	for _, field := range s.Fields {
+		if !field.Creatable || field.DefaultValueInterface == nil {
+			continue
+		}
+		if _, isZero := field.ValueOf(ctx, rv); isZero {
+			if err := field.Set(ctx, rv, field.DefaultValueInterface); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// copyColumns 计算 COPY 写入的列
+// 由数据库生成默认值的字段（自增主键、default 标签为数据库表达式）在所有实体中均为零值时省略该列，
+// 部分实体为零值时返回 ErrCopyDefaultValue，避免把零值当作数据写入
+func copyColumns[T any](ctx context.Context, s *schema.Schema, entities []*T) ([]*schema.Field, error) {
+	columns := make([]*schema.Field, 0, len(s.DBNames))
+	for _, name := range s.DBNames {
+		field := s.FieldsByDBName[name]
+		if !field.Creatable {
+			continue
+		}
+		if field.HasDefaultValue && field.DefaultValueInterface == nil {
+			switch zeros := countZero(ctx, field, entities); zeros {
+			case len(entities):
+				continue
+			case 0:
+			default:
+				return nil, fmt.Errorf("%w: %s", ErrCopyDefaultValue, field.Name)
+			}
+		}
+		columns = append(columns, field)
+	}
+	return columns, nil
+}
+
+// countZero 字段在实体中为零值的数量
+func countZero[T any](ctx context.Context, field *schema.Field, entities []*T) int {
+	zeros := 0
+	for _, entity := range entities {
+		if _, isZero := field.ValueOf(ctx, reflect.ValueOf(entity).Elem()); isZero {
+			zeros++
+		}
+	}
+	return zeros
+}
+
+// copyRows 将实体转换为 COPY 数据行，实现 driver.Valuer 的字段使用其数据库值
+func copyRows[T any](ctx context.Context, columns []*schema.Field, entities []*T) ([][]any, error) {
+	rows := make([][]any, len(entities))
+	for i, entity := range entities {
+		rv := reflect.ValueOf(entity).Elem()
+		row := make([]any, len(columns))
+		for j, field := range columns {
+			value, _ := field.ValueOf(ctx, rv)
+			if array, ok := value.(StringArray); ok {
+				// pgx 按原生数组编码
+				value = []string(array)
+			} else if valuer, ok := value.(driver.Valuer); ok {
+				v, err := valuer.Value()
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %w", field.Name, err)
+				}
+				value = v
+			}
+			row[j] = value
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
@@ -0,0 +1,216 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm/schema"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// copyEntity 带默认值与数组字段的实体
+type copyEntity struct {
+	BaseEntity
+	Name   string
+	Status string      `gorm:"default:active"`
+	Token  string      `gorm:"default:gen_random_uuid()"`
+	Tags   StringArray `gorm:"type:text[]"`
+}
+
+// copySchema 解析 copyEntity 的 schema
+func copySchema(t *testing.T) *schema.Schema {
+	t.Helper()
+	s, err := NewGormRepository[copyEntity, int](openTestDB(t)).schema()
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	return s
+}
+
+func TestCopyColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		entities []*copyEntity
+		want     []string
+		wantErr  error
+	}{
+		{
+			name:     "database defaults omitted",
+			entities: []*copyEntity{{Name: "a"}, {Name: "b"}},
+			want:     []string{"created_at", "updated_at", "deleted_at", "version", "name", "status", "tags"},
+		},
+		{
+			name: "explicit values kept",
+			entities: []*copyEntity{
+				{BaseEntity: BaseEntity{ID: 1}, Token: "t1"},
+				{BaseEntity: BaseEntity{ID: 2}, Token: "t2"},
+			},
+			want: []string{"id", "created_at", "updated_at", "deleted_at", "version", "name", "status", "token", "tags"},
+		},
+		{name: "mixed database default", entities: []*copyEntity{{Token: "t1"}, {}}, wantErr: ErrCopyDefaultValue},
+		{
+			name:     "mixed primary key",
+			entities: []*copyEntity{{BaseEntity: BaseEntity{ID: 1}}, {}},
+			wantErr:  ErrCopyDefaultValue,
+		},
+	}
+	s := copySchema(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := copyColumns(context.Background(), s, tt.entities)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			got := make([]string, len(columns))
+			for i, field := range columns {
+				got[i] = field.DBName
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("columns = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillDefaultsAndCopyRows(t *testing.T) {
+	s := copySchema(t)
+	ctx := context.Background()
+	entities := []*copyEntity{
+		{Name: "a", Tags: StringArray{"x", "y"}},
+		{Name: "b", Status: "disabled"},
+	}
+	for _, entity := range entities {
+		if err := fillDefaults(ctx, s, reflect.ValueOf(entity).Elem()); err != nil {
+			t.Fatalf("fill defaults: %v", err)
+		}
+	}
+	if entities[0].Status != "active" || entities[1].Status != "disabled" {
+		t.Fatalf("status = %q, %q", entities[0].Status, entities[1].Status)
+	}
+	if entities[0].Version != 1 || entities[0].Token != "" {
+		t.Fatalf("version = %d, token = %q", entities[0].Version, entities[0].Token)
+	}
+
+	columns, err := copyColumns(ctx, s, entities)
+	if err != nil {
+		t.Fatalf("columns: %v", err)
+	}
+	rows, err := copyRows(ctx, columns, entities)
+	if err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+	values := make(map[string]any, len(columns))
+	for i, field := range columns {
+		values[field.DBName] = rows[0][i]
+	}
+	// 数组按原生数组编码，driver.Valuer 使用其数据库值
+	if tags, ok := values["tags"].([]string); !ok || len(tags) != 2 {
+		t.Errorf("tags = %#v, want []string", values["tags"])
+	}
+	if values["deleted_at"] != nil {
+		t.Errorf("deleted_at = %#v, want nil", values["deleted_at"])
+	}
+}
+
+func TestCreateBatchWithOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		emails       []string
+		opts         *BatchOptions
+		wantStored   int
+		wantProgress []BatchProgress
+		wantCode     int // 期望的错误码，0 表示成功
+	}{
+		{
+			name:   "chunked",
+			emails: []string{"a", "b", "c", "d", "e"},
+			opts:   &BatchOptions{Size: 2},
+			wantProgress: []BatchProgress{
+				{Chunk: 1, Chunks: 3, Rows: 2, Written: 2, Total: 5},
+				{Chunk: 2, Chunks: 3, Rows: 2, Written: 4, Total: 5},
+				{Chunk: 3, Chunks: 3, Rows: 1, Written: 5, Total: 5},
+			},
+			wantStored: 5,
+		},
+		{
+			name:       "copy falls back to insert",
+			emails:     []string{"a", "b"},
+			opts:       &BatchOptions{UseCopy: true},
+			wantStored: 2,
+			wantProgress: []BatchProgress{
+				{Chunk: 1, Chunks: 1, Rows: 2, Written: 2, Total: 2},
+			},
+		},
+		{
+			name:   "failed chunk rolls back all",
+			emails: []string{"a", "b", "c", "a"},
+			opts:   &BatchOptions{Size: 2},
+			wantProgress: []BatchProgress{
+				{Chunk: 1, Chunks: 2, Rows: 2, Written: 2, Total: 4},
+			},
+			wantCode: apperrors.Conflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewGormRepository[uniqueEntity, int](openTestDB(t, &uniqueEntity{}))
+			ctx := context.Background()
+			entities := make([]*uniqueEntity, len(tt.emails))
+			for i, email := range tt.emails {
+				entities[i] = &uniqueEntity{Email: fmt.Sprintf("%s@example.com", email)}
+			}
+			var progress []BatchProgress
+			tt.opts.OnProgress = func(p BatchProgress) { progress = append(progress, p) }
+
+			err := repo.CreateBatchWithOptions(ctx, entities, tt.opts)
+			if tt.wantCode != 0 {
+				if appErr, ok := apperrors.AsAppError(err); !ok || appErr.Code != tt.wantCode {
+					t.Fatalf("err = %v, want code %d", err, tt.wantCode)
+				}
+			} else if err != nil {
+				t.Fatalf("create batch: %v", err)
+			}
+			if !reflect.DeepEqual(progress, tt.wantProgress) {
+				t.Errorf("progress = %+v, want %+v", progress, tt.wantProgress)
+			}
+			stored, err := repo.List(ctx)
+			if err != nil || len(stored) != tt.wantStored {
+				t.Fatalf("stored = %d, %v, want %d", len(stored), err, tt.wantStored)
+			}
+			if tt.wantStored > 0 && (entities[0].ID == 0 || entities[0].Version != 1) {
+				t.Errorf("entity = %+v, want id and version filled", entities[0])
+			}
+		})
+	}
+}
+
+func TestCreateBatchJoinsOuterTx(t *testing.T) {
+	repo := NewGormRepository[uniqueEntity, int](openTestDB(t, &uniqueEntity{}))
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	err := repo.WithTx(ctx, func(ctx context.Context) error {
+		entities := []*uniqueEntity{{Email: "a@example.com"}, {Email: "b@example.com"}}
+		if err := repo.CreateBatchWithOptions(ctx, entities, &BatchOptions{Size: 1, UseCopy: true}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("err = %v, want abort", err)
+	}
+	// 外层事务回滚时批量写入一并回滚
+	if stored, _ := repo.List(ctx); len(stored) != 0 {
+		t.Fatalf("stored = %d, want 0", len(stored))
+	}
+}
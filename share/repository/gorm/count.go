@@ -0,0 +1,60 @@
+package gorm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"soliton-client/share/repository"
+)
+
+// countModeOf 确定实际使用的总数统计方式
+// 只有 PostgreSQL 提供可用的查询计划估算，其他数据库按精确统计
+func countModeOf(db *gorm.DB, mode repository.CountMode) repository.CountMode {
+	switch mode {
+	case repository.CountNone:
+		return repository.CountNone
+	case repository.CountEstimated:
+		if dialectOf(db) == PostgreSQL {
+			return repository.CountEstimated
+		}
+		return repository.CountExact
+	default:
+		return repository.CountExact
+	}
+}
+
+// explainPlan PostgreSQL EXPLAIN (FORMAT JSON) 的输出
+type explainPlan []struct {
+	Plan struct {
+		PlanRows float64 `json:"Plan Rows"`
+	} `json:"Plan"`
+}
+
+// estimateCount 使用 PostgreSQL 查询计划估算满足条件的行数
+// 估算值依赖表统计信息（ANALYZE），条件越复杂误差越大，仅用于展示大致数量
+func estimateCount[T any](db *gorm.DB, conditions []*repository.Condition) (int64, error) {
+	var entities []*T
+	stmt := ApplyConditions(db.Session(&gorm.Session{DryRun: true}), conditions...).
+		Model(new(T)).
+		Find(&entities)
+	if stmt.Error != nil {
+		return 0, TranslateError(stmt.Error)
+	}
+
+	var raw string
+	err := db.Raw("EXPLAIN (FORMAT JSON) "+stmt.Statement.SQL.String(), stmt.Statement.Vars...).Row().Scan(&raw)
+	if err != nil {
+		return 0, TranslateError(err)
+	}
+
+	var plan explainPlan
+	if err := json.Unmarshal([]byte(raw), &plan); err != nil {
+		return 0, fmt.Errorf("parse query plan: %w", err)
+	}
+	if len(plan) == 0 {
+		return 0, fmt.Errorf("empty query plan")
+	}
+	return int64(plan[0].Plan.PlanRows), nil
+}
@@ -0,0 +1,73 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"soliton-client/share/repository"
+)
+
+func TestCountModeOf(t *testing.T) {
+	tests := []struct {
+		dbType DatabaseType
+		mode   repository.CountMode
+		want   repository.CountMode
+	}{
+		{dbType: PostgreSQL, mode: repository.CountEstimated, want: repository.CountEstimated},
+		{dbType: MySQL, mode: repository.CountEstimated, want: repository.CountExact},
+		{dbType: SQLite, mode: repository.CountNone, want: repository.CountNone},
+		{dbType: PostgreSQL, mode: "", want: repository.CountExact},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dbType)+"/"+string(tt.mode), func(t *testing.T) {
+			if got := countModeOf(dryRunDB(t, tt.dbType), tt.mode); got != tt.want {
+				t.Errorf("countModeOf = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageCountModes(t *testing.T) {
+	repo := NewGormRepository[autoEntity, int](openTestDB(t, &autoEntity{}))
+	ctx := context.Background()
+	entities := make([]*autoEntity, 5)
+	for i := range entities {
+		entities[i] = &autoEntity{Name: string(rune('a' + i))}
+	}
+	if err := repo.CreateBatch(ctx, entities); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		mode      repository.CountMode
+		page      int
+		wantItems int
+		wantTotal int64
+		wantKind  repository.TotalKind
+		wantMore  bool
+	}{
+		{name: "exact", mode: repository.CountExact, page: 1, wantItems: 2, wantTotal: 5, wantKind: repository.TotalExact, wantMore: true},
+		{name: "exact last page", mode: repository.CountExact, page: 3, wantItems: 1, wantTotal: 5, wantKind: repository.TotalExact},
+		{name: "none", mode: repository.CountNone, page: 2, wantItems: 2, wantKind: repository.TotalUnknown, wantMore: true},
+		{name: "none last page", mode: repository.CountNone, page: 3, wantItems: 1, wantKind: repository.TotalUnknown},
+		{name: "none exactly full", mode: repository.CountNone, page: 4, wantKind: repository.TotalUnknown},
+		// SQLite 不支持估算，按精确统计
+		{name: "estimated fallback", mode: repository.CountEstimated, page: 1, wantItems: 2, wantTotal: 5, wantKind: repository.TotalExact, wantMore: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := repository.NewPageRequest(tt.page, 2).WithOrderBy("id", false).WithCountMode(tt.mode)
+			got, err := repo.Page(ctx, request)
+			if err != nil {
+				t.Fatalf("page: %v", err)
+			}
+			if len(got.Items) != tt.wantItems || got.Total != tt.wantTotal || got.TotalKind != tt.wantKind || got.HasMore != tt.wantMore {
+				t.Fatalf("page = items %d total %d kind %s more %v", len(got.Items), got.Total, got.TotalKind, got.HasMore)
+			}
+			if tt.wantItems > 0 && got.Items[0].Name != string(rune('a'+(tt.page-1)*2)) {
+				t.Errorf("first item = %s", got.Items[0].Name)
+			}
+		})
+	}
+}
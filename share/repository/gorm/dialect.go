@@ -0,0 +1,74 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// dialectOf 获取 GORM 连接对应的数据库类型
+func dialectOf(db *gorm.DB) DatabaseType {
+	if db == nil || db.Dialector == nil {
+		return ""
+	}
+	return DatabaseType(db.Dialector.Name())
+}
+
+// likeEscape LIKE 转义子句（转义符为反斜杠）
+// MySQL 字符串字面量中的反斜杠本身需要转义
+func likeEscape(db *gorm.DB) string {
+	if dialectOf(db) == MySQL {
+		return `ESCAPE '\\'`
+	}
+	return `ESCAPE '\'`
+}
+
+// likeClause LIKE 条件片段（模式参数为 ?，\ 为转义符）
+func likeClause(db *gorm.DB, field string, not bool) string {
+	op := "LIKE"
+	if not {
+		op = "NOT LIKE"
+	}
+	return fmt.Sprintf("%s %s ? %s", field, op, likeEscape(db))
+}
+
+// iLikeClause 不区分大小写的 LIKE 条件片段（模式参数为 ?，\ 为转义符）
+// PostgreSQL 使用 ILIKE；其他数据库两侧转为小写后比较，不依赖列的排序规则
+// （如 MySQL 二进制排序规则下 LIKE 区分大小写）
+func iLikeClause(db *gorm.DB, field string) string {
+	if dialectOf(db) == PostgreSQL {
+		return fmt.Sprintf("%s ILIKE ? %s", field, likeEscape(db))
+	}
+	return fmt.Sprintf("LOWER(%s) LIKE LOWER(?) %s", field, likeEscape(db))
+}
+
+// asPgError 提取 PostgreSQL 驱动错误
+func asPgError(err error) (*pgconn.PgError, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr, true
+	}
+	return nil, false
+}
+
+// asMySQLError 提取 MySQL 驱动错误
+func asMySQLError(err error) (*mysql.MySQLError, bool) {
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return myErr, true
+	}
+	return nil, false
+}
+
+// asSQLiteError 提取 SQLite 驱动错误
+func asSQLiteError(err error) (sqlite3.Error, bool) {
+	var liteErr sqlite3.Error
+	if errors.As(err, &liteErr) {
+		return liteErr, true
+	}
+	return sqlite3.Error{}, false
+}
@@ -0,0 +1,84 @@
+package gorm
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"soliton-client/share/repository"
+)
+
+func TestLikeSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		dbType  DatabaseType
+		cond    *repository.Condition
+		wantSQL string
+	}{
+		{name: "pg ilike", dbType: PostgreSQL, cond: repository.Contains("name", "50%_off"), wantSQL: `name ILIKE '%50\%\_off%' ESCAPE '\'`},
+		{name: "mysql ilike", dbType: MySQL, cond: repository.StartsWith("name", "Al"), wantSQL: `LOWER(name) LIKE LOWER('Al%') ESCAPE '\\'`},
+		{name: "sqlite ilike", dbType: SQLite, cond: repository.EndsWith("name", `a\b`), wantSQL: `LOWER(name) LIKE LOWER("%a\\b") ESCAPE '\'`},
+		{name: "pg like keeps pattern", dbType: PostgreSQL, cond: repository.Like("name", "a_b%"), wantSQL: `name LIKE 'a_b%' ESCAPE '\'`},
+		{name: "pg like literal", dbType: PostgreSQL, cond: repository.LikeLiteral("name", "a_b"), wantSQL: `name LIKE '%a\_b%' ESCAPE '\'`},
+		{name: "pg not like literal", dbType: PostgreSQL, cond: repository.NotLikeLiteral("name", "%"), wantSQL: `name NOT LIKE '%\%%' ESCAPE '\'`},
+		{
+			name:    "raw pattern",
+			dbType:  PostgreSQL,
+			cond:    repository.NewCondition("name", repository.OpLike, "a_c%"),
+			wantSQL: `name LIKE 'a_c%' ESCAPE '\'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dryRunSQL(ApplyCondition(dryRunDB(t, tt.dbType), tt.cond))
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !strings.Contains(got, "WHERE "+tt.wantSQL) {
+				t.Errorf("sql = %q\nwant %q", got, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestLikeMatching(t *testing.T) {
+	repo := NewQueryableGormRepository[personEntity, int](openTestDB(t, &personEntity{}))
+	ctx := context.Background()
+	people := []*personEntity{{Name: "Alice"}, {Name: "ALICE_2"}, {Name: "alicex2"}, {Name: "100% off"}, {Name: `C:\dir`}}
+	if err := repo.CreateBatch(ctx, people); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cond *repository.Condition
+		want []string
+	}{
+		{name: "contains ignores case", cond: repository.Contains("name", "lic"), want: []string{"ALICE_2", "Alice", "alicex2"}},
+		{name: "underscore is literal", cond: repository.Contains("name", "e_2"), want: []string{"ALICE_2"}},
+		{name: "percent is literal", cond: repository.StartsWith("name", "100%"), want: []string{"100% off"}},
+		{name: "backslash is literal", cond: repository.EndsWith("name", `\DIR`), want: []string{`C:\dir`}},
+		{name: "like pattern", cond: repository.Like("name", "A%"), want: []string{"ALICE_2", "Alice", "alicex2"}},
+		{name: "like literal", cond: repository.LikeLiteral("name", "% o"), want: []string{"100% off"}},
+		{name: "not like pattern", cond: repository.NotLike("name", "%_2"), want: []string{"100% off", "Alice", `C:\dir`}},
+		{name: "not like literal", cond: repository.NotLikeLiteral("name", "_"), want: []string{"100% off", "Alice", `C:\dir`, "alicex2"}},
+		{name: "raw pattern wildcard", cond: repository.ILike("name", "alice_2"), want: []string{"ALICE_2", "alicex2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.Where(ctx, tt.cond)
+			if err != nil {
+				t.Fatalf("where: %v", err)
+			}
+			got := make([]string, len(found))
+			for i, p := range found {
+				got[i] = p.Name
+			}
+			sort.Strings(got)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("names = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// ConstraintKind 约束违反类型
+type ConstraintKind string
+
+const (
+	ConstraintUnique     ConstraintKind = "unique"      // 唯一约束
+	ConstraintForeignKey ConstraintKind = "foreign_key" // 外键约束
+	ConstraintNotNull    ConstraintKind = "not_null"    // 非空约束
+	ConstraintCheck      ConstraintKind = "check"       // 检查约束
+)
+
+// ConstraintError 数据库约束违反错误，保留触发的约束名与字段
+type ConstraintError struct {
+	Kind       ConstraintKind // 约束类型
+	Constraint string         // 约束或索引名称
+	Field      string         // 触发约束的字段（无法识别时为空）
+	Err        error          // 原始驱动错误
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%s constraint violated (constraint=%s, field=%s): %v", e.Kind, e.Constraint, e.Field, e.Err)
+}
+
+// Unwrap 实现 errors.Unwrap 接口
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// TranslateError 将数据库驱动错误转换为 AppError
+// 支持 PostgreSQL、MySQL、SQLite，映射规则:
+//
+//	唯一约束   -> Conflict
+//	外键约束   -> BadRequest
+//	非空/检查  -> Validation
+//	超时       -> Timeout
+//
+// 无法识别的错误原样返回
+func TranslateError(err error) error {
+	if err == nil || apperrors.IsAppError(err) {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.Wrap(apperrors.NotFound, "资源不存在", err)
+	}
+
+	if constraintErr := classifyConstraint(err); constraintErr != nil {
+		return constraintAppError(constraintErr)
+	}
+
+	if isTimeout(err) {
+		return apperrors.ErrTimeout("数据库操作超时", err)
+	}
+
+	return err
+}
+
+// constraintAppError 根据约束类型构造 AppError
+// 消息只使用识别出的字段名，约束或索引名称属于库表结构细节，不返回给客户端
+func constraintAppError(e *ConstraintError) *apperrors.AppError {
+	switch e.Kind {
+	case ConstraintUnique:
+		if e.Field == "" {
+			return apperrors.Wrap(apperrors.Conflict, "数据已存在", e)
+		}
+		return apperrors.Wrap(apperrors.Conflict, fmt.Sprintf("%s 已存在", e.Field), e)
+	case ConstraintForeignKey:
+		return apperrors.Wrap(apperrors.BadRequest, "关联数据不存在或仍被引用", e)
+	case ConstraintNotNull:
+		if e.Field == "" {
+			return apperrors.Wrap(apperrors.Validation, "必填字段不能为空", e)
+		}
+		return apperrors.Wrap(apperrors.Validation, fmt.Sprintf("%s 不能为空", e.Field), e)
+	default:
+		return apperrors.Wrap(apperrors.Validation, "数据校验失败", e)
+	}
+}
+
+// classifyConstraint 识别各数据库的约束违反错误
+func classifyConstraint(err error) *ConstraintError {
+	if pgErr, ok := asPgError(err); ok {
+		c := &ConstraintError{Constraint: pgErr.ConstraintName, Field: pgErr.ColumnName, Err: err}
+		switch pgErr.Code {
+		case "23505":
+			c.Kind = ConstraintUnique
+			if c.Field == "" {
+				// Detail 形如: Key (email)=(a@b.com) already exists.
+				c.Field = between(pgErr.Detail, "Key (", ")=")
+			}
+		case "23503":
+			c.Kind = ConstraintForeignKey
+		case "23502":
+			c.Kind = ConstraintNotNull
+		case "23514":
+			c.Kind = ConstraintCheck
+		default:
+			return nil
+		}
+		return c
+	}
+
+	if myErr, ok := asMySQLError(err); ok {
+		c := &ConstraintError{Err: err}
+		switch myErr.Number {
+		case 1062: // Duplicate entry 'x' for key 'users.idx_users_email'
+			c.Kind = ConstraintUnique
+			c.Constraint = between(myErr.Message, "for key '", "'")
+			c.Field = indexColumn(c.Constraint)
+		case 1451, 1452: // Cannot delete or update a parent row / Cannot add or update a child row
+			c.Kind = ConstraintForeignKey
+			c.Constraint = between(myErr.Message, "CONSTRAINT `", "`")
+		case 1048, 1364: // Column 'name' cannot be null / Field 'name' doesn't have a default value
+			c.Kind = ConstraintNotNull
+			c.Field = between(myErr.Message, "'", "'")
+		case 3819: // Check constraint 'chk' is violated.
+			c.Kind = ConstraintCheck
+			c.Constraint = between(myErr.Message, "'", "'")
+		default:
+			return nil
+		}
+		return c
+	}
+
+	if liteErr, ok := asSQLiteError(err); ok && liteErr.Code == sqlite3.ErrConstraint {
+		// 错误信息形如: UNIQUE constraint failed: users.email
+		c := &ConstraintError{Err: err}
+		detail := liteErr.Error()
+		if idx := strings.Index(detail, "failed: "); idx >= 0 {
+			c.Constraint = strings.TrimSpace(detail[idx+len("failed: "):])
+			c.Field = columnOf(c.Constraint)
+		}
+		switch liteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			c.Kind = ConstraintUnique
+		case sqlite3.ErrConstraintForeignKey:
+			c.Kind = ConstraintForeignKey
+		case sqlite3.ErrConstraintNotNull:
+			c.Kind = ConstraintNotNull
+		case sqlite3.ErrConstraintCheck:
+			c.Kind = ConstraintCheck
+			c.Field = ""
+		default:
+			return nil
+		}
+		return c
+	}
+
+	return nil
+}
+
+// isTimeout 判断是否为超时错误
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if pgErr, ok := asPgError(err); ok {
+		// 57014 query_canceled（statement_timeout）
+		return pgErr.Code == "57014"
+	}
+	if myErr, ok := asMySQLError(err); ok {
+		// 3024 查询执行时间超过 max_execution_time
+		return myErr.Number == 3024
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// between 截取 start 与 end 之间的内容
+func between(s, start, end string) string {
+	i := strings.Index(s, start)
+	if i < 0 {
+		return ""
+	}
+	s = s[i+len(start):]
+	j := strings.Index(s, end)
+	if j < 0 {
+		return ""
+	}
+	return s[:j]
+}
+
+// columnOf 从 "table.column[, table.column]" 中提取字段名
+func columnOf(s string) string {
+	parts := strings.Split(s, ",")
+	columns := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if idx := strings.LastIndex(part, "."); idx >= 0 {
+			part = part[idx+1:]
+		}
+		columns = append(columns, part)
+	}
+	return strings.Join(columns, ", ")
+}
+
+// indexColumn 从 GORM 默认命名的索引名中提取字段名
+// 索引名形如 table.idx_table_column 或 table.uni_table_column（MySQL 8 带表名前缀），
+// 无法识别命名规则或缺少表名时返回空
+func indexColumn(key string) string {
+	idx := strings.LastIndex(key, ".")
+	if idx < 0 {
+		return ""
+	}
+	table, name := key[:idx], key[idx+1:]
+	for _, prefix := range []string{"idx_", "uni_"} {
+		if column, ok := strings.CutPrefix(name, prefix+table+"_"); ok && column != "" {
+			return column
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+
+	apperrors "soliton-client/share/errors"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int    // 0 表示原样返回
+		wantMsg  string // 为空时不校验
+		wantKind ConstraintKind
+	}{
+		{name: "nil", err: nil},
+		{name: "unknown", err: errors.New("boom")},
+		{name: "app error", err: apperrors.ErrForbidden("no"), wantCode: apperrors.Forbidden},
+		{name: "record not found", err: fmt.Errorf("get: %w", gorm.ErrRecordNotFound), wantCode: apperrors.NotFound},
+		{name: "deadline", err: context.DeadlineExceeded, wantCode: apperrors.Timeout},
+		{
+			name:     "pg unique from detail",
+			err:      &pgconn.PgError{Code: "23505", ConstraintName: "idx_users_email", Detail: "Key (email)=(a@b.com) already exists."},
+			wantCode: apperrors.Conflict, wantMsg: "email 已存在", wantKind: ConstraintUnique,
+		},
+		{
+			name:     "pg unique without detail",
+			err:      &pgconn.PgError{Code: "23505", ConstraintName: "idx_users_email"},
+			wantCode: apperrors.Conflict, wantMsg: "数据已存在", wantKind: ConstraintUnique,
+		},
+		{name: "pg foreign key", err: &pgconn.PgError{Code: "23503"}, wantCode: apperrors.BadRequest, wantKind: ConstraintForeignKey},
+		{
+			name:     "pg not null",
+			err:      &pgconn.PgError{Code: "23502", ColumnName: "name"},
+			wantCode: apperrors.Validation, wantMsg: "name 不能为空", wantKind: ConstraintNotNull,
+		},
+		{name: "pg check", err: &pgconn.PgError{Code: "23514"}, wantCode: apperrors.Validation, wantKind: ConstraintCheck},
+		{name: "pg statement timeout", err: &pgconn.PgError{Code: "57014"}, wantCode: apperrors.Timeout},
+		{name: "pg other", err: &pgconn.PgError{Code: "42P01"}},
+		{
+			name:     "mysql duplicate",
+			err:      &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a' for key 'users.idx_users_email'"},
+			wantCode: apperrors.Conflict, wantMsg: "email 已存在", wantKind: ConstraintUnique,
+		},
+		{
+			name:     "mysql duplicate custom key",
+			err:      &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a' for key 'users.email_unique'"},
+			wantCode: apperrors.Conflict, wantMsg: "数据已存在", wantKind: ConstraintUnique,
+		},
+		{
+			name:     "mysql duplicate primary",
+			err:      &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"},
+			wantCode: apperrors.Conflict, wantMsg: "数据已存在", wantKind: ConstraintUnique,
+		},
+		{
+			name:     "mysql null column",
+			err:      &mysql.MySQLError{Number: 1048, Message: "Column 'name' cannot be null"},
+			wantCode: apperrors.Validation, wantMsg: "name 不能为空", wantKind: ConstraintNotNull,
+		},
+		{name: "mysql max execution time", err: &mysql.MySQLError{Number: 3024}, wantCode: apperrors.Timeout},
+		{
+			name:     "sqlite unique",
+			err:      sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique},
+			wantCode: apperrors.Conflict, wantMsg: "数据已存在", wantKind: ConstraintUnique,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TranslateError(tt.err)
+			if tt.wantCode == 0 {
+				if got != tt.err {
+					t.Fatalf("TranslateError = %v, want original error", got)
+				}
+				return
+			}
+			appErr, ok := apperrors.AsAppError(got)
+			if !ok || appErr.Code != tt.wantCode {
+				t.Fatalf("TranslateError = %v, want code %d", got, tt.wantCode)
+			}
+			if tt.wantMsg != "" && appErr.Message != tt.wantMsg {
+				t.Errorf("message = %q, want %q", appErr.Message, tt.wantMsg)
+			}
+			var constraintErr *ConstraintError
+			if tt.wantKind != "" && (!errors.As(got, &constraintErr) || constraintErr.Kind != tt.wantKind) {
+				t.Errorf("constraint = %+v, want kind %s", constraintErr, tt.wantKind)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("translated error does not wrap the driver error")
+			}
+		})
+	}
+}
+
+// uniqueEntity 带唯一索引的实体
+type uniqueEntity struct {
+	BaseEntity
+	Email string `gorm:"uniqueIndex"`
+}
+
+func TestTranslateErrorFromSQLite(t *testing.T) {
+	repo := NewGormRepository[uniqueEntity, int](openTestDB(t, &uniqueEntity{}))
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &uniqueEntity{Email: "a@b.com"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	err := repo.Create(ctx, &uniqueEntity{Email: "a@b.com"})
+	appErr, ok := apperrors.AsAppError(err)
+	if !ok || appErr.Code != apperrors.Conflict || appErr.Message != "email 已存在" {
+		t.Fatalf("duplicate create = %v, want Conflict on email", err)
+	}
+
+	// GetByID 查询不到时返回 nil，不视为错误
+	if entity, err := repo.GetByID(ctx, 42); entity != nil || err != nil {
+		t.Fatalf("GetByID missing = %v, %v, want nil, nil", entity, err)
+	}
+}
@@ -36,6 +36,8 @@ type DatabaseConfig struct {
 	ConnMaxIdleTime time.Duration   // 连接最大空闲时间
 	LogLevel        logger.LogLevel // 日志级别
 	SlowThreshold   time.Duration   // 慢查询阈值
+	IDStrategy      IDStrategy      // 默认主键生成策略（仅作用于非自增主键）
+	SnowflakeNodeID int64           // Snowflake 节点 ID（0-1023）
 }
 
 // DefaultConfig 默认配置
@@ -52,6 +54,7 @@ func DefaultConfig() *DatabaseConfig {
 		ConnMaxIdleTime: 10 * time.Minute,
 		LogLevel:        logger.Info,
 		SlowThreshold:   200 * time.Millisecond,
+		IDStrategy:      IDAutoIncrement,
 	}
 }
 
@@ -75,6 +78,11 @@ func (f *DatabaseFactory) Create() (*gorm.DB, error) {
 		return nil, err
 	}
 
+	idGenerators, err := NewIDGenerators(f.config)
+	if err != nil {
+		return nil, err
+	}
+
 	// GORM 配置
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(f.config.LogLevel),
@@ -101,6 +109,14 @@ func (f *DatabaseFactory) Create() (*gorm.DB, error) {
 	// 注册审计回调
 	RegisterAuditCallbacks(db)
 
+	// 注册主键生成回调
+	if err := RegisterIDCallbacks(db, idGenerators); err != nil {
+		return nil, fmt.Errorf("failed to register id callbacks: %w", err)
+	}
+
+	// 注册实体校验回调
+	RegisterValidationCallbacks(db)
+
 	return db, nil
 }
 
@@ -167,6 +183,11 @@ func CreateWithDSN(dbType DatabaseType, dsn string, config *DatabaseConfig) (*go
 		return nil, fmt.Errorf("unsupported database type: %s", dbType)
 	}
 
+	idGenerators, err := NewIDGenerators(config)
+	if err != nil {
+		return nil, err
+	}
+
 	// GORM 配置
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(config.LogLevel),
@@ -193,6 +214,14 @@ func CreateWithDSN(dbType DatabaseType, dsn string, config *DatabaseConfig) (*go
 	// 注册审计回调
 	RegisterAuditCallbacks(db)
 
+	// 注册主键生成回调
+	if err := RegisterIDCallbacks(db, idGenerators); err != nil {
+		return nil, fmt.Errorf("failed to register id callbacks: %w", err)
+	}
+
+	// 注册实体校验回调
+	RegisterValidationCallbacks(db)
+
 	return db, nil
 }
 
@@ -1,9 +1,12 @@
 package gorm
 
 import (
+	"context"
+	"reflect"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 // BeforeCreate GORM 创建前钩子
@@ -31,7 +34,7 @@ func (e *BaseEntity) BeforeUpdate(tx *gorm.DB) error {
 }
 
 // RegisterAuditCallbacks 注册审计回调到 GORM
-// 为所有实现 Auditable 接口的实体自动填充审计字段
+// 为所有实现 Auditable 接口的实体自动填充审计字段（兼容单个实体与批量切片）
 func RegisterAuditCallbacks(db *gorm.DB) {
 	// 创建前回调
 	db.Callback().Create().Before("gorm:create").Register("audit:before_create", func(tx *gorm.DB) {
@@ -40,27 +43,9 @@ func RegisterAuditCallbacks(db *gorm.DB) {
 		}
 
 		now := time.Now()
-
-		// 设置创建时间
-		if field := tx.Statement.Schema.LookUpField("CreatedAt"); field != nil {
-			if _, isZero := field.ValueOf(tx.Statement.Context, tx.Statement.ReflectValue); isZero {
-				_ = field.Set(tx.Statement.Context, tx.Statement.ReflectValue, now)
-			}
-		}
-
-		// 设置更新时间
-		if field := tx.Statement.Schema.LookUpField("UpdatedAt"); field != nil {
-			if _, isZero := field.ValueOf(tx.Statement.Context, tx.Statement.ReflectValue); isZero {
-				_ = field.Set(tx.Statement.Context, tx.Statement.ReflectValue, now)
-			}
-		}
-
-		// 设置版本号
-		if field := tx.Statement.Schema.LookUpField("Version"); field != nil {
-			if val, isZero := field.ValueOf(tx.Statement.Context, tx.Statement.ReflectValue); isZero || val == 0 {
-				_ = field.Set(tx.Statement.Context, tx.Statement.ReflectValue, 1)
-			}
-		}
+		forEachModel(tx.Statement.ReflectValue, func(rv reflect.Value) {
+			fillCreateAudit(tx.Statement.Context, tx.Statement.Schema, rv, now)
+		})
 	})
 
 	// 更新前回调
@@ -69,18 +54,50 @@ func RegisterAuditCallbacks(db *gorm.DB) {
 			return
 		}
 
-		// 设置更新时间
-		if field := tx.Statement.Schema.LookUpField("UpdatedAt"); field != nil {
-			_ = field.Set(tx.Statement.Context, tx.Statement.ReflectValue, time.Now())
+		now := time.Now()
+		forEachModel(tx.Statement.ReflectValue, func(rv reflect.Value) {
+			fillUpdateAudit(tx.Statement.Context, tx.Statement.Schema, rv, now)
+		})
+	})
+}
+
+// fillCreateAudit 填充单个实体的创建审计字段
+func fillCreateAudit(ctx context.Context, s *schema.Schema, rv reflect.Value, now time.Time) {
+	// 设置创建时间
+	if field := s.LookUpField("CreatedAt"); field != nil {
+		if _, isZero := field.ValueOf(ctx, rv); isZero {
+			_ = field.Set(ctx, rv, now)
 		}
+	}
+
+	// 设置更新时间
+	if field := s.LookUpField("UpdatedAt"); field != nil {
+		if _, isZero := field.ValueOf(ctx, rv); isZero {
+			_ = field.Set(ctx, rv, now)
+		}
+	}
+
+	// 设置版本号
+	if field := s.LookUpField("Version"); field != nil {
+		if val, isZero := field.ValueOf(ctx, rv); isZero || val == 0 {
+			_ = field.Set(ctx, rv, 1)
+		}
+	}
+}
+
+// fillUpdateAudit 填充单个实体的更新审计字段
+func fillUpdateAudit(ctx context.Context, s *schema.Schema, rv reflect.Value, now time.Time) {
+	// 设置更新时间
+	if field := s.LookUpField("UpdatedAt"); field != nil {
+		_ = field.Set(ctx, rv, now)
+	}
 
-		// 版本号递增（乐观锁）
-		if field := tx.Statement.Schema.LookUpField("Version"); field != nil {
-			if val, _ := field.ValueOf(tx.Statement.Context, tx.Statement.ReflectValue); val != nil {
-				if version, ok := val.(int); ok {
-					_ = field.Set(tx.Statement.Context, tx.Statement.ReflectValue, version+1)
-				}
+	// 版本号递增（乐观锁）
+	if field := s.LookUpField("Version"); field != nil {
+		if val, _ := field.ValueOf(ctx, rv); val != nil {
+			if version, ok := val.(int); ok {
+				_ = field.Set(ctx, rv, version+1)
 			}
 		}
-	})
+	}
 }
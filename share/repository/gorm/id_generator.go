@@ -0,0 +1,342 @@
+package gorm
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// IDStrategy 主键生成策略
+type IDStrategy string
+
+const (
+	IDAutoIncrement IDStrategy = "auto_increment" // 数据库自增（默认）
+	IDUUIDv7        IDStrategy = "uuidv7"         // UUIDv7，按时间有序
+	IDULID          IDStrategy = "ulid"           // ULID，按时间有序且 URL 友好
+	IDSnowflake     IDStrategy = "snowflake"      // Snowflake，64 位整数
+)
+
+// timeNow 主键生成器使用的时钟
+var timeNow = time.Now
+
+// IDGenerator 主键生成器接口
+type IDGenerator interface {
+	// NextID 生成下一个主键
+	NextID() (interface{}, error)
+}
+
+// IDStrategyProvider 实体声明自身主键策略的接口
+// 实现此接口的实体在创建前由回调自动分配主键
+type IDStrategyProvider interface {
+	IDStrategy() IDStrategy
+}
+
+// ==================== UUIDv7 ====================
+
+// UUIDv7Generator UUIDv7 主键生成器
+type UUIDv7Generator struct{}
+
+// NextID 生成 UUIDv7 字符串
+func (UUIDv7Generator) NextID() (interface{}, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	return id.String(), nil
+}
+
+// ==================== ULID ====================
+
+// crockfordAlphabet ULID 使用的 Crockford Base32 字母表
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator ULID 主键生成器
+// 同一毫秒内生成的 ULID 单调递增
+type ULIDGenerator struct {
+	mu       sync.Mutex
+	lastMs   uint64
+	lastRand [10]byte
+}
+
+// NewULIDGenerator 创建 ULID 生成器
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{}
+}
+
+// NextID 生成 26 位 ULID 字符串
+func (g *ULIDGenerator) NextID() (interface{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(timeNow().UnixMilli())
+	if ms <= g.lastMs {
+		// 同一毫秒（或时钟回拨）时在上一个随机数基础上递增，保证单调
+		ms = g.lastMs
+		if !incrementBytes(g.lastRand[:]) {
+			return nil, errors.New("ulid: random component overflow within the same millisecond")
+		}
+	} else {
+		if _, err := rand.Read(g.lastRand[:]); err != nil {
+			return nil, fmt.Errorf("ulid: failed to read random bytes: %w", err)
+		}
+		g.lastMs = ms
+	}
+
+	// 48 位时间戳 + 80 位随机数，共 128 位
+	hi := ms<<16 | uint64(g.lastRand[0])<<8 | uint64(g.lastRand[1])
+	var lo uint64
+	for _, b := range g.lastRand[2:] {
+		lo = lo<<8 | uint64(b)
+	}
+
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:]), nil
+}
+
+// incrementBytes 按大端序对字节数组加一，溢出时返回 false
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ==================== Snowflake ====================
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxNodeID    = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSequence  = 1<<snowflakeSequenceBits - 1
+)
+
+// SnowflakeEpoch Snowflake 起始纪元（2024-01-01 UTC）
+var SnowflakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeGenerator Snowflake 主键生成器
+// 结构: 41 位毫秒时间戳 | 10 位节点 ID | 12 位序列号
+type SnowflakeGenerator struct {
+	mu       sync.Mutex
+	nodeID   int64
+	epochMs  int64
+	lastMs   int64
+	sequence int64
+}
+
+// NewSnowflakeGenerator 创建 Snowflake 生成器，节点 ID 取值范围 0-1023
+func NewSnowflakeGenerator(nodeID int64) (*SnowflakeGenerator, error) {
+	if nodeID < 0 || nodeID > snowflakeMaxNodeID {
+		return nil, fmt.Errorf("snowflake node id must be between 0 and %d, got %d", snowflakeMaxNodeID, nodeID)
+	}
+	return &SnowflakeGenerator{
+		nodeID:  nodeID,
+		epochMs: SnowflakeEpoch.UnixMilli(),
+	}, nil
+}
+
+// NextID 生成 Snowflake 整数主键
+func (g *SnowflakeGenerator) NextID() (interface{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := timeNow().UnixMilli()
+	if now < g.lastMs {
+		// 时钟回拨时沿用上一个时间戳，避免生成重复主键
+		now = g.lastMs
+	}
+
+	if now == g.lastMs {
+		g.sequence = (g.sequence + 1) & snowflakeMaxSequence
+		if g.sequence == 0 {
+			// 当前毫秒序列号用尽，等待下一毫秒
+			for now <= g.lastMs {
+				time.Sleep(100 * time.Microsecond)
+				now = timeNow().UnixMilli()
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMs = now
+
+	id := (now-g.epochMs)<<(snowflakeNodeBits+snowflakeSequenceBits) |
+		g.nodeID<<snowflakeSequenceBits |
+		g.sequence
+	return id, nil
+}
+
+// ==================== 生成器注册表 ====================
+
+// IDGenerators 主键生成器注册表
+type IDGenerators struct {
+	defaultStrategy IDStrategy
+	generators      map[IDStrategy]IDGenerator
+}
+
+// NewIDGenerators 根据数据库配置创建主键生成器注册表
+func NewIDGenerators(config *DatabaseConfig) (*IDGenerators, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	snowflake, err := NewSnowflakeGenerator(config.SnowflakeNodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy := config.IDStrategy
+	if strategy == "" {
+		strategy = IDAutoIncrement
+	}
+
+	g := &IDGenerators{
+		defaultStrategy: strategy,
+		generators: map[IDStrategy]IDGenerator{
+			IDUUIDv7:    UUIDv7Generator{},
+			IDULID:      NewULIDGenerator(),
+			IDSnowflake: snowflake,
+		},
+	}
+	if strategy != IDAutoIncrement {
+		if _, ok := g.generators[strategy]; !ok {
+			return nil, fmt.Errorf("unsupported id strategy: %s", strategy)
+		}
+	}
+	return g, nil
+}
+
+// Register 注册或替换指定策略的生成器
+func (g *IDGenerators) Register(strategy IDStrategy, generator IDGenerator) {
+	g.generators[strategy] = generator
+}
+
+// Get 获取指定策略的生成器
+func (g *IDGenerators) Get(strategy IDStrategy) (IDGenerator, bool) {
+	generator, ok := g.generators[strategy]
+	return generator, ok
+}
+
+// Assign 为单个实体分配主键（主键非零值时跳过）
+func (g *IDGenerators) Assign(ctx context.Context, s *schema.Schema, rv reflect.Value) error {
+	field := s.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+	if _, isZero := field.ValueOf(ctx, rv); !isZero {
+		return nil
+	}
+
+	strategy := g.strategyOf(rv)
+	if strategy == "" {
+		// 未声明策略的实体，仅对非自增主键使用默认策略
+		if field.AutoIncrement {
+			return nil
+		}
+		strategy = g.defaultStrategy
+	}
+	if strategy == IDAutoIncrement {
+		return nil
+	}
+
+	generator, ok := g.generators[strategy]
+	if !ok {
+		return fmt.Errorf("no id generator registered for strategy: %s", strategy)
+	}
+	id, err := generator.NextID()
+	if err != nil {
+		return err
+	}
+
+	// 整数主键生成器用于字符串主键列时转为十进制字符串
+	if field.FieldType.Kind() == reflect.String {
+		if n, ok := id.(int64); ok {
+			id = strconv.FormatInt(n, 10)
+		}
+	}
+	return field.Set(ctx, rv, id)
+}
+
+// strategyOf 获取实体声明的主键策略
+func (g *IDGenerators) strategyOf(rv reflect.Value) IDStrategy {
+	var target interface{}
+	switch {
+	case rv.Kind() == reflect.Ptr:
+		target = rv.Interface()
+	case rv.CanAddr():
+		target = rv.Addr().Interface()
+	default:
+		target = rv.Interface()
+	}
+	if provider, ok := target.(IDStrategyProvider); ok {
+		return provider.IDStrategy()
+	}
+	return ""
+}
+
+// idGeneratorsPlugin 主键生成器在 GORM 插件表中的名称
+const idGeneratorsPlugin = "soliton:id_generators"
+
+// Name 实现 gorm.Plugin 接口
+func (g *IDGenerators) Name() string {
+	return idGeneratorsPlugin
+}
+
+// Initialize 实现 gorm.Plugin 接口，注册主键生成回调
+func (g *IDGenerators) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register("id:before_create", func(tx *gorm.DB) {
+		if tx.Statement.Schema == nil {
+			return
+		}
+		forEachModel(tx.Statement.ReflectValue, func(rv reflect.Value) {
+			if err := g.Assign(tx.Statement.Context, tx.Statement.Schema, rv); err != nil {
+				_ = tx.AddError(err)
+			}
+		})
+	})
+}
+
+// RegisterIDCallbacks 注册主键生成回调到 GORM
+// 在插入前为主键为零值的实体分配主键，使主键在写入数据库前即可确定
+func RegisterIDCallbacks(db *gorm.DB, generators *IDGenerators) error {
+	return db.Use(generators)
+}
+
+// idGeneratorsOf 获取已注册到 GORM 的主键生成器
+func idGeneratorsOf(db *gorm.DB) (*IDGenerators, bool) {
+	generators, ok := db.Config.Plugins[idGeneratorsPlugin].(*IDGenerators)
+	return generators, ok
+}
+
+// forEachModel 遍历反射值中的每个实体（兼容单个实体与批量切片）
+func forEachModel(rv reflect.Value, fn func(rv reflect.Value)) {
+	rv = reflect.Indirect(rv)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elem := rv.Index(i)
+			if elem.Kind() == reflect.Ptr && elem.IsNil() {
+				continue
+			}
+			fn(elem)
+		}
+	case reflect.Struct:
+		fn(rv)
+	}
+}
@@ -0,0 +1,267 @@
+package gorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB 打开内存 SQLite 数据库并注册主键生成回调
+func openTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	// 内存数据库每个连接独立，固定为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	generators, err := NewIDGenerators(&DatabaseConfig{IDStrategy: IDAutoIncrement})
+	if err != nil {
+		t.Fatalf("id generators: %v", err)
+	}
+	if err := RegisterIDCallbacks(db, generators); err != nil {
+		t.Fatalf("register id callbacks: %v", err)
+	}
+	if len(models) > 0 {
+		if err := db.AutoMigrate(models...); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	return db
+}
+
+// fakeClock 可控时钟，替换 timeNow 直到测试结束
+func fakeClock(t *testing.T, now func() time.Time) {
+	t.Helper()
+	orig := timeNow
+	timeNow = now
+	t.Cleanup(func() { timeNow = orig })
+}
+
+func TestULIDGenerator(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		times []time.Time // 每次生成时的时钟
+	}{
+		{name: "same millisecond", times: []time.Time{base, base, base, base}},
+		{name: "advancing clock", times: []time.Time{base, base.Add(time.Millisecond), base.Add(2 * time.Millisecond)}},
+		{name: "clock rollback", times: []time.Time{base, base.Add(-time.Second), base.Add(-time.Millisecond), base}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var now time.Time
+			fakeClock(t, func() time.Time { return now })
+
+			g := NewULIDGenerator()
+			var prev string
+			for i, ts := range tt.times {
+				now = ts
+				v, err := g.NextID()
+				if err != nil {
+					t.Fatalf("NextID #%d: %v", i, err)
+				}
+				id := v.(string)
+				if len(id) != 26 {
+					t.Fatalf("NextID #%d = %q, want 26 chars", i, id)
+				}
+				if id <= prev {
+					t.Fatalf("NextID #%d = %q, not greater than %q", i, id, prev)
+				}
+				// 时钟回拨时沿用上一个时间戳
+				if prev != "" && !ts.After(tt.times[i-1]) && id[:10] != prev[:10] {
+					t.Fatalf("NextID #%d timestamp %q, want %q", i, id[:10], prev[:10])
+				}
+				prev = id
+			}
+		})
+	}
+}
+
+func TestULIDGeneratorOverflow(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock(t, func() time.Time { return now })
+
+	g := NewULIDGenerator()
+	if _, err := g.NextID(); err != nil {
+		t.Fatalf("NextID: %v", err)
+	}
+	for i := range g.lastRand {
+		g.lastRand[i] = 0xff
+	}
+	if _, err := g.NextID(); err == nil {
+		t.Fatal("expected overflow error within the same millisecond")
+	}
+}
+
+func TestSnowflakeGenerator(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	decode := func(id int64) (ms, node, seq int64) {
+		return id>>(snowflakeNodeBits+snowflakeSequenceBits) + SnowflakeEpoch.UnixMilli(),
+			id >> snowflakeSequenceBits & snowflakeMaxNodeID,
+			id & snowflakeMaxSequence
+	}
+
+	tests := []struct {
+		name     string
+		count    int                  // 生成数量
+		clock    func(call int) int64 // 第 call 次读取时钟时的毫秒偏移
+		wantLast [2]int64             // 最后一个 ID 的毫秒偏移与序列号
+	}{
+		{
+			name:     "sequence within millisecond",
+			count:    3,
+			clock:    func(int) int64 { return 0 },
+			wantLast: [2]int64{0, 2},
+		},
+		{
+			// 序列号用尽后等待下一毫秒，序列号归零
+			name:  "sequence overflow",
+			count: snowflakeMaxSequence + 2,
+			clock: func(call int) int64 {
+				if call > snowflakeMaxSequence+1 {
+					return 1
+				}
+				return 0
+			},
+			wantLast: [2]int64{1, 0},
+		},
+		{
+			// 时钟回拨时沿用上一个时间戳
+			name:  "clock rollback",
+			count: 2,
+			clock: func(call int) int64 {
+				if call == 1 {
+					return 10
+				}
+				return 5
+			},
+			wantLast: [2]int64{10, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			fakeClock(t, func() time.Time {
+				calls++
+				return base.Add(time.Duration(tt.clock(calls)) * time.Millisecond)
+			})
+
+			g, err := NewSnowflakeGenerator(7)
+			if err != nil {
+				t.Fatalf("NewSnowflakeGenerator: %v", err)
+			}
+			var prev int64 = -1
+			for i := 0; i < tt.count; i++ {
+				v, err := g.NextID()
+				if err != nil {
+					t.Fatalf("NextID #%d: %v", i, err)
+				}
+				id := v.(int64)
+				if id <= prev {
+					t.Fatalf("NextID #%d = %d, not greater than %d", i, id, prev)
+				}
+				prev = id
+			}
+
+			ms, node, seq := decode(prev)
+			if node != 7 {
+				t.Errorf("node = %d, want 7", node)
+			}
+			if got := ms - base.UnixMilli(); got != tt.wantLast[0] || seq != tt.wantLast[1] {
+				t.Errorf("last id = (ms +%d, seq %d), want (ms +%d, seq %d)", got, seq, tt.wantLast[0], tt.wantLast[1])
+			}
+		})
+	}
+}
+
+func TestNewSnowflakeGeneratorNodeID(t *testing.T) {
+	for _, node := range []int64{-1, snowflakeMaxNodeID + 1} {
+		if _, err := NewSnowflakeGenerator(node); err == nil {
+			t.Errorf("NewSnowflakeGenerator(%d) expected error", node)
+		}
+	}
+}
+
+// ulidEntity 声明 ULID 主键策略的实体
+type ulidEntity struct {
+	ID   string `gorm:"primaryKey;size:26"`
+	Name string
+}
+
+func (ulidEntity) IDStrategy() IDStrategy { return IDULID }
+
+// autoEntity 自增主键实体
+type autoEntity struct {
+	BaseEntity
+	Name string
+}
+
+func TestIDCallback(t *testing.T) {
+	db := openTestDB(t, &ulidEntity{}, &autoEntity{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		entity *ulidEntity
+		check  func(t *testing.T, id string)
+	}{
+		{
+			name:   "assigns zero id",
+			entity: &ulidEntity{Name: "a"},
+			check: func(t *testing.T, id string) {
+				if len(id) != 26 || strings.Trim(id, crockfordAlphabet) != "" {
+					t.Errorf("id = %q, want ULID", id)
+				}
+			},
+		},
+		{
+			name:   "keeps non-zero id",
+			entity: &ulidEntity{ID: "preset-id", Name: "b"},
+			check: func(t *testing.T, id string) {
+				if id != "preset-id" {
+					t.Errorf("id = %q, want preset-id", id)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := db.WithContext(ctx).Create(tt.entity).Error; err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			tt.check(t, tt.entity.ID)
+		})
+	}
+
+	t.Run("batch assigns each", func(t *testing.T) {
+		batch := []*ulidEntity{{Name: "c"}, {ID: "preset-2", Name: "d"}, {Name: "e"}}
+		if err := db.WithContext(ctx).Create(&batch).Error; err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if batch[1].ID != "preset-2" || batch[0].ID == "" || batch[2].ID <= batch[0].ID {
+			t.Errorf("ids = %q %q %q", batch[0].ID, batch[1].ID, batch[2].ID)
+		}
+	})
+
+	t.Run("auto increment untouched", func(t *testing.T) {
+		e := &autoEntity{Name: "f"}
+		if err := db.WithContext(ctx).Create(e).Error; err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if e.ID != 1 {
+			t.Errorf("id = %d, want 1", e.ID)
+		}
+	})
+}
@@ -0,0 +1,289 @@
+package gorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"soliton-client/share/repository"
+)
+
+// ErrOperatorNotSupported 当前数据库不支持该操作符
+var ErrOperatorNotSupported = errors.New("operator is not supported by this database")
+
+// applyPostgresOperator 应用 PostgreSQL 专有的 JSONB 与数组操作符
+// 其他数据库返回 ErrOperatorNotSupported，避免条件被静默忽略导致查询范围扩大
+func applyPostgresOperator(db *gorm.DB, cond *repository.Condition) *gorm.DB {
+	if dialectOf(db) != PostgreSQL {
+		return withError(db, fmt.Errorf("%w: %s on %s", ErrOperatorNotSupported, cond.Operator, dialectOf(db)))
+	}
+
+	switch cond.Operator {
+	case repository.OpJSONPathEqual:
+		path, ok := cond.Value.(repository.JSONPath)
+		if !ok {
+			return withError(db, fmt.Errorf("operator %s requires repository.JSONPath value", cond.Operator))
+		}
+		// nil 匹配 JSON null 或路径不存在（#>> 均返回 NULL），不能按文本 "<nil>" 比较
+		if path.Value == nil {
+			return db.Where(fmt.Sprintf("%s #>> ? IS NULL", cond.Field), pgArray{path.Path})
+		}
+		return db.Where(fmt.Sprintf("%s #>> ? = ?", cond.Field), pgArray{path.Path}, fmt.Sprint(path.Value))
+	case repository.OpJSONContains:
+		raw, err := json.Marshal(cond.Value)
+		if err != nil {
+			return withError(db, fmt.Errorf("operator %s: %w", cond.Operator, err))
+		}
+		return db.Where(fmt.Sprintf("%s @> ?::jsonb", cond.Field), string(raw))
+	case repository.OpJSONHasKey:
+		// 使用 jsonb_exists 代替 ? 操作符，避免与占位符冲突
+		return db.Where(fmt.Sprintf("jsonb_exists(%s, ?)", cond.Field), cond.Value)
+	case repository.OpArrayContains, repository.OpArrayOverlaps:
+		if kind := reflect.ValueOf(cond.Value).Kind(); kind != reflect.Slice && kind != reflect.Array {
+			return withError(db, fmt.Errorf("operator %s requires a slice value, got %T", cond.Operator, cond.Value))
+		}
+		op := "@>"
+		if cond.Operator == repository.OpArrayOverlaps {
+			op = "&&"
+		}
+		return db.Where(fmt.Sprintf("%s %s ?", cond.Field, op), pgArray{cond.Value})
+	default:
+		return withError(db, fmt.Errorf("unsupported operator: %s", cond.Operator))
+	}
+}
+
+// pgArray 以 PostgreSQL 数组字面量传参
+// GORM 会将切片参数展开为 (?, ?)，包装后作为单个参数传递
+type pgArray struct {
+	values interface{}
+}
+
+// Value 实现 driver.Valuer 接口
+func (a pgArray) Value() (driver.Value, error) {
+	rv := reflect.ValueOf(a.values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("array operator requires a slice value, got %T", a.values)
+	}
+
+	elems := make([]string, rv.Len())
+	for i := range elems {
+		elem := rv.Index(i).Interface()
+		switch v := elem.(type) {
+		case nil:
+			elems[i] = "NULL"
+		case string:
+			elems[i] = quoteArrayElem(v)
+		default:
+			elems[i] = quoteArrayElem(fmt.Sprint(v))
+		}
+	}
+	return "{" + strings.Join(elems, ",") + "}", nil
+}
+
+// quoteArrayElem 转义数组元素
+func quoteArrayElem(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// JSONB JSON 列类型
+// PostgreSQL 映射为 jsonb，MySQL 为 json，SQLite 为 text；序列化为 JSON 时与 Data 一致
+type JSONB[T any] struct {
+	Data T
+}
+
+// NewJSONB 创建 JSON 列值
+func NewJSONB[T any](data T) JSONB[T] {
+	return JSONB[T]{Data: data}
+}
+
+// Value 实现 driver.Valuer 接口
+func (j JSONB[T]) Value() (driver.Value, error) {
+	raw, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+// Scan 实现 sql.Scanner 接口
+func (j *JSONB[T]) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		var zero T
+		j.Data = zero
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSONB", value)
+	}
+	return json.Unmarshal(raw, &j.Data)
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (j JSONB[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (j *JSONB[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.Data)
+}
+
+// GormDataType 通用数据类型
+func (JSONB[T]) GormDataType() string {
+	return "json"
+}
+
+// GormDBDataType 按数据库返回列类型
+func (JSONB[T]) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch dialectOf(db) {
+	case PostgreSQL:
+		return "jsonb"
+	case MySQL:
+		return "json"
+	default:
+		return "text"
+	}
+}
+
+// StringArray 文本数组列类型
+// PostgreSQL 映射为 text[]，其他数据库以 JSON 文本存储
+type StringArray []string
+
+// Value 实现 driver.Valuer 接口，以 JSON 写入（PostgreSQL 写入时由 GormValue 转为数组字面量）
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal([]string(a))
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+// Scan 实现 sql.Scanner 接口，兼容 PostgreSQL 数组字面量与 JSON 数组
+func (a *StringArray) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("cannot scan %T into StringArray", value)
+	}
+
+	if strings.HasPrefix(s, "[") {
+		var values []string
+		if err := json.Unmarshal([]byte(s), &values); err != nil {
+			return err
+		}
+		*a = values
+		return nil
+	}
+	values, err := parsePgArray(s)
+	if err != nil {
+		return err
+	}
+	*a = values
+	return nil
+}
+
+// GormDataType 通用数据类型
+func (StringArray) GormDataType() string {
+	return "string_array"
+}
+
+// GormDBDataType 按数据库返回列类型
+func (StringArray) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch dialectOf(db) {
+	case PostgreSQL:
+		return "text[]"
+	case MySQL:
+		return "json"
+	default:
+		return "text"
+	}
+}
+
+// GormValue 按数据库生成写入值，PostgreSQL 使用数组字面量
+func (a StringArray) GormValue(_ context.Context, db *gorm.DB) clause.Expr {
+	if a == nil {
+		return clause.Expr{SQL: "NULL"}
+	}
+	if dialectOf(db) == PostgreSQL {
+		literal, _ := pgArray{[]string(a)}.Value()
+		return clause.Expr{SQL: "?", Vars: []interface{}{literal}}
+	}
+	value, err := a.Value()
+	if err != nil {
+		_ = db.AddError(err)
+	}
+	return clause.Expr{SQL: "?", Vars: []interface{}{value}}
+}
+
+// parsePgArray 解析一维 PostgreSQL 文本数组字面量，如 {a,"b c",NULL}
+func parsePgArray(s string) ([]string, error) {
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("invalid array literal: %q", s)
+	}
+	body := s[1 : len(s)-1]
+	values := make([]string, 0)
+	if body == "" {
+		return values, nil
+	}
+
+	var (
+		current strings.Builder
+		quoted  bool
+		escaped bool
+		inQuote bool
+	)
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case escaped:
+			current.WriteByte(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			inQuote = !inQuote
+			quoted = true
+		case c == ',' && !inQuote:
+			values = append(values, arrayElem(current.String(), quoted))
+			current.Reset()
+			quoted = false
+		default:
+			current.WriteByte(c)
+		}
+	}
+	values = append(values, arrayElem(current.String(), quoted))
+	return values, nil
+}
+
+// arrayElem 处理未加引号的 NULL 元素（StringArray 无法表示 NULL，按空字符串处理）
+func arrayElem(s string, quoted bool) string {
+	if !quoted && s == "NULL" {
+		return ""
+	}
+	return s
+}
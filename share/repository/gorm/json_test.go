@@ -0,0 +1,170 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"soliton-client/share/repository"
+)
+
+func TestPostgresOperators(t *testing.T) {
+	tests := []struct {
+		name    string
+		cond    *repository.Condition
+		wantSQL string // 期望 WHERE 片段
+		wantErr bool
+	}{
+		{
+			name:    "json path equal",
+			cond:    repository.JSONPathEq("profile", "address.city", "Shanghai"),
+			wantSQL: `profile #>> '{"address","city"}' = 'Shanghai'`,
+		},
+		{
+			name:    "json contains",
+			cond:    repository.JSONContains("profile", map[string]interface{}{"vip": true}),
+			wantSQL: `profile @> '{"vip":true}'::jsonb`,
+		},
+		{
+			name:    "json path nil",
+			cond:    repository.JSONPathEq("profile", "address.city", nil),
+			wantSQL: `profile #>> '{"address","city"}' IS NULL`,
+		},
+		{
+			name:    "json path bool",
+			cond:    repository.JSONPathEq("profile", "vip", true),
+			wantSQL: `profile #>> '{"vip"}' = 'true'`,
+		},
+		{name: "json has key", cond: repository.JSONHasKey("profile", "phone"), wantSQL: `jsonb_exists(profile, 'phone')`},
+		{
+			name:    "array contains",
+			cond:    repository.ArrayContains("tags", []string{"go", `a"b`}),
+			wantSQL: `tags @> '{"go","a\"b"}'`,
+		},
+		{name: "array overlaps ints", cond: repository.ArrayOverlaps("scores", []int{1, 2}), wantSQL: `scores && '{"1","2"}'`},
+		{name: "array requires slice", cond: repository.ArrayContains("tags", "go"), wantErr: true},
+		{
+			name:    "json path requires path value",
+			cond:    repository.NewCondition("profile", repository.OpJSONPathEqual, "x"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dryRunSQL(ApplyCondition(dryRunDB(t, PostgreSQL), tt.cond))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("sql = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !strings.Contains(got, "WHERE "+tt.wantSQL) {
+				t.Errorf("sql = %q, want %q", got, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestOperatorNotSupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		dbType DatabaseType
+		cond   *repository.Condition
+	}{
+		{name: "json on mysql", dbType: MySQL, cond: repository.JSONHasKey("profile", "phone")},
+		{name: "array on sqlite", dbType: SQLite, cond: repository.ArrayContains("tags", []string{"go"})},
+		{name: "unknown operator", dbType: PostgreSQL, cond: repository.NewCondition("name", "~~~", "x")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dryRunSQL(ApplyCondition(dryRunDB(t, tt.dbType), tt.cond))
+			if !errors.Is(err, ErrOperatorNotSupported) {
+				t.Fatalf("err = %v, want ErrOperatorNotSupported", err)
+			}
+		})
+	}
+}
+
+func TestStringArrayScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    StringArray
+		wantErr bool
+	}{
+		{name: "nil", value: nil, want: nil},
+		{name: "empty literal", value: "{}", want: StringArray{}},
+		{name: "plain literal", value: []byte("{a,b}"), want: StringArray{"a", "b"}},
+		{name: "quoted and escaped", value: `{"a b","c,d","e\"f",NULL,"NULL"}`, want: StringArray{"a b", "c,d", `e"f`, "", "NULL"}},
+		{name: "json array", value: `["x","y"]`, want: StringArray{"x", "y"}},
+		{name: "invalid literal", value: "a,b", wantErr: true},
+		{name: "unsupported type", value: 42, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got StringArray
+			err := got.Scan(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("scan = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scan = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	// 写入数组字面量后能原样解析
+	literal, err := pgArray{[]string{`a"b`, `c\d`, "e,f"}}.Value()
+	if err != nil {
+		t.Fatalf("literal: %v", err)
+	}
+	var roundTrip StringArray
+	if err := roundTrip.Scan(literal); err != nil || !reflect.DeepEqual(roundTrip, StringArray{`a"b`, `c\d`, "e,f"}) {
+		t.Fatalf("round trip = %#v, %v", roundTrip, err)
+	}
+}
+
+// documentEntity 带 JSON 与数组列的实体
+type documentEntity struct {
+	BaseEntity
+	Meta JSONB[map[string]int]
+	Tags StringArray
+}
+
+func TestJSONColumnsRoundTrip(t *testing.T) {
+	repo := NewGormRepository[documentEntity, int](openTestDB(t, &documentEntity{}))
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		entity *documentEntity
+	}{
+		{name: "values", entity: &documentEntity{Meta: NewJSONB(map[string]int{"views": 3}), Tags: StringArray{"go", "db"}}},
+		{name: "nil values", entity: &documentEntity{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Create(ctx, tt.entity); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			got, err := repo.GetByID(ctx, tt.entity.ID)
+			if err != nil || got == nil {
+				t.Fatalf("get: %v", err)
+			}
+			if !reflect.DeepEqual(got.Meta, tt.entity.Meta) || !reflect.DeepEqual(got.Tags, tt.entity.Tags) {
+				t.Errorf("got meta %v tags %#v, want meta %v tags %#v", got.Meta, got.Tags, tt.entity.Meta, tt.entity.Tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"soliton-client/share/repository"
+)
+
+var (
+	// ErrLockOutsideTx 在事务外使用行锁
+	ErrLockOutsideTx = errors.New("row lock requires a transaction in context, use WithTx or BeginTx first")
+
+	// ErrLockNotSupported 当前数据库不支持行锁
+	ErrLockNotSupported = errors.New("row-level locking is not supported by this database")
+)
+
+// applyLock 应用行锁子句
+// SQLite 没有行级锁（写事务会锁定整个数据库），显式返回错误而不是静默忽略
+func applyLock(db *gorm.DB, inTx bool, strength repository.LockStrength, wait repository.LockWait) *gorm.DB {
+	if strength == repository.LockNone {
+		if wait != repository.LockWaitDefault {
+			return withError(db, fmt.Errorf("lock wait option %q requires ForUpdate or ForShare", wait))
+		}
+		return db
+	}
+	if !inTx {
+		return withError(db, ErrLockOutsideTx)
+	}
+
+	switch dialectOf(db) {
+	case PostgreSQL, MySQL:
+		return db.Clauses(clause.Locking{
+			Strength: string(strength),
+			Options:  string(wait),
+		})
+	default:
+		return withError(db, fmt.Errorf("%w: %s", ErrLockNotSupported, dialectOf(db)))
+	}
+}
+
+// withError 在新会话上记录错误，避免污染共享的 DB 或事务实例
+func withError(db *gorm.DB, err error) *gorm.DB {
+	db = db.Session(&gorm.Session{})
+	_ = db.AddError(err)
+	return db
+}
@@ -0,0 +1,85 @@
+package gorm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"soliton-client/share/repository"
+)
+
+// dryRunDB 创建不连接数据库的 DryRun 实例，用于断言生成的 SQL
+func dryRunDB(t *testing.T, dbType DatabaseType) *gorm.DB {
+	t.Helper()
+	var dialector gorm.Dialector
+	switch dbType {
+	case PostgreSQL:
+		dialector = postgres.New(postgres.Config{DSN: "host=localhost dbname=test"})
+	case MySQL:
+		dialector = mysql.New(mysql.Config{DSN: "root@tcp(localhost:3306)/test", SkipInitializeWithVersion: true})
+	default:
+		return openTestDB(t).Session(&gorm.Session{DryRun: true})
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open %s: %v", dbType, err)
+	}
+	return db
+}
+
+// dryRunSQL 生成 Find 语句（参数已内联）
+func dryRunSQL(db *gorm.DB) (string, error) {
+	var entities []*autoEntity
+	stmt := db.Find(&entities)
+	if stmt.Error != nil {
+		return "", stmt.Error
+	}
+	return stmt.Dialector.Explain(stmt.Statement.SQL.String(), stmt.Statement.Vars...), nil
+}
+
+func TestApplyLock(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   DatabaseType
+		inTx     bool
+		strength repository.LockStrength
+		wait     repository.LockWait
+		wantSQL  string // 期望 SQL 后缀
+		wantErr  error  // 为 nil 且 wantSQL 为空时期望任意错误
+	}{
+		{name: "no lock", dbType: PostgreSQL, wantSQL: `"deleted_at" IS NULL`},
+		{name: "pg for update", dbType: PostgreSQL, inTx: true, strength: repository.LockForUpdate, wantSQL: "FOR UPDATE"},
+		{name: "pg for share skip locked", dbType: PostgreSQL, inTx: true, strength: repository.LockForShare, wait: repository.LockSkipLocked, wantSQL: "FOR SHARE SKIP LOCKED"},
+		{name: "mysql nowait", dbType: MySQL, inTx: true, strength: repository.LockForUpdate, wait: repository.LockNoWait, wantSQL: "FOR UPDATE NOWAIT"},
+		{name: "outside transaction", dbType: PostgreSQL, strength: repository.LockForUpdate, wantErr: ErrLockOutsideTx},
+		{name: "sqlite unsupported", dbType: SQLite, inTx: true, strength: repository.LockForUpdate, wantErr: ErrLockNotSupported},
+		{name: "wait without lock", dbType: PostgreSQL, inTx: true, wait: repository.LockNoWait},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := dryRunDB(t, tt.dbType)
+			got, err := dryRunSQL(applyLock(db, tt.inTx, tt.strength, tt.wait))
+			if tt.wantSQL == "" {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				// 错误记录在新会话上，不影响原实例
+				if db.Error != nil {
+					t.Errorf("shared db polluted: %v", db.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !strings.HasSuffix(got, tt.wantSQL) {
+				t.Errorf("sql = %q, want suffix %q", got, tt.wantSQL)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"soliton-client/share/repository"
+)
+
+// NativeQuery 类型化原生 SQL 查询
+// SQL 使用 @name 形式的命名参数，在上下文事务中执行并扫描到 T；
+// 与查询构建器一样是不可变的，设置参数会返回新的查询
+type NativeQuery[T any] struct {
+	db     *gorm.DB
+	sql    string
+	params map[string]interface{}
+}
+
+// NewNativeQuery 创建原生 SQL 查询
+func NewNativeQuery[T any](db *gorm.DB, sql string) *NativeQuery[T] {
+	return &NativeQuery[T]{
+		db:     db,
+		sql:    strings.TrimRight(strings.TrimSpace(sql), ";"),
+		params: make(map[string]interface{}),
+	}
+}
+
+// NewNativeQueryFS 从文件系统（通常为 embed.FS）加载 .sql 文件创建原生 SQL 查询
+func NewNativeQueryFS[T any](db *gorm.DB, fsys fs.FS, name string) (*NativeQuery[T], error) {
+	raw, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("load native query %s: %w", name, err)
+	}
+	return NewNativeQuery[T](db, string(raw)), nil
+}
+
+// Native 创建以当前实体为结果类型的原生 SQL 查询
+func (r *GormRepository[T, ID]) Native(sql string) *NativeQuery[T] {
+	return NewNativeQuery[T](r.db, sql)
+}
+
+// Param 设置命名参数
+func (q *NativeQuery[T]) Param(name string, value interface{}) *NativeQuery[T] {
+	nq := q.clone()
+	nq.params[name] = value
+	return nq
+}
+
+// Params 批量设置命名参数
+func (q *NativeQuery[T]) Params(params map[string]interface{}) *NativeQuery[T] {
+	nq := q.clone()
+	for name, value := range params {
+		nq.params[name] = value
+	}
+	return nq
+}
+
+// SQL 原始 SQL
+func (q *NativeQuery[T]) SQL() string {
+	return q.sql
+}
+
+// Find 执行查询，返回结果列表
+func (q *NativeQuery[T]) Find(ctx context.Context) ([]*T, error) {
+	return q.find(ctx, q.sql, q.params)
+}
+
+// First 执行查询，返回第一条结果，没有结果时返回 nil
+func (q *NativeQuery[T]) First(ctx context.Context) (*T, error) {
+	items, err := q.find(ctx, q.wrap("SELECT * FROM (%s) native_query LIMIT 1"), q.params)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+// Count 统计查询结果数量
+func (q *NativeQuery[T]) Count(ctx context.Context) (int64, error) {
+	if err := q.checkParams(); err != nil {
+		return 0, err
+	}
+	var count int64
+	err := dbFromContext(ctx, q.db).
+		Raw(q.wrap("SELECT COUNT(*) FROM (%s) native_query"), bindArgs(q.sql, q.params)...).
+		Scan(&count).Error
+	if err != nil {
+		return 0, TranslateError(err)
+	}
+	return count, nil
+}
+
+// Page 分页查询，将原查询包装为子查询后统计总数并追加 LIMIT/OFFSET
+// 原查询的 ORDER BY 位于子查询中，分页结果的顺序依赖数据库保留子查询顺序（PostgreSQL、MySQL、SQLite 均满足）
+func (q *NativeQuery[T]) Page(ctx context.Context, page, size int) (*repository.PageResult[*T], error) {
+	total, err := q.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]interface{}, len(q.params)+2)
+	for name, value := range q.params {
+		params[name] = value
+	}
+	params["native_limit"] = size
+	params["native_offset"] = (page - 1) * size
+
+	items, err := q.find(ctx, q.wrap("SELECT * FROM (%s) native_query LIMIT @native_limit OFFSET @native_offset"), params)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewPageResult(items, total, page, size), nil
+}
+
+// Exec 执行不返回结果的原生 SQL（如 UPDATE、DELETE），返回影响行数
+func (q *NativeQuery[T]) Exec(ctx context.Context) (int64, error) {
+	if err := q.checkParams(); err != nil {
+		return 0, err
+	}
+	result := dbFromContext(ctx, q.db).Exec(q.sql, bindArgs(q.sql, q.params)...)
+	if result.Error != nil {
+		return 0, TranslateError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// find 执行查询并扫描到 T
+func (q *NativeQuery[T]) find(ctx context.Context, sql string, params map[string]interface{}) ([]*T, error) {
+	if err := q.checkParams(); err != nil {
+		return nil, err
+	}
+	var items []*T
+	if err := dbFromContext(ctx, q.db).Raw(sql, bindArgs(sql, params)...).Scan(&items).Error; err != nil {
+		return nil, TranslateError(err)
+	}
+	return items, nil
+}
+
+// wrap 将原查询包装为子查询
+func (q *NativeQuery[T]) wrap(format string) string {
+	return fmt.Sprintf(format, q.sql)
+}
+
+// clone 复制查询
+func (q *NativeQuery[T]) clone() *NativeQuery[T] {
+	params := make(map[string]interface{}, len(q.params))
+	for name, value := range q.params {
+		params[name] = value
+	}
+	return &NativeQuery[T]{db: q.db, sql: q.sql, params: params}
+}
+
+// checkParams 检查 SQL 中的命名参数是否均已设置，避免生成错误的语句
+func (q *NativeQuery[T]) checkParams() error {
+	var missing []string
+	for _, name := range namedParams(q.sql) {
+		if _, ok := q.params[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("native query: missing named parameters: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// bindArgs 构造绑定参数，SQL 不含命名参数时不传参数表
+// GORM 只在 SQL 含 @name 时展开参数表，否则会把参数表当作普通参数绑定而报错
+func bindArgs(sql string, params map[string]interface{}) []interface{} {
+	if len(namedParams(sql)) == 0 {
+		return nil
+	}
+	return []interface{}{params}
+}
+
+// namedParams 提取 SQL 中的 @name 命名参数（忽略字符串字面量与 @@ 操作符）
+func namedParams(sql string) []string {
+	var (
+		names   []string
+		seen    = make(map[string]struct{})
+		inQuote bool
+	)
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if c == '\'' {
+			inQuote = !inQuote
+			continue
+		}
+		if inQuote || c != '@' || (i > 0 && sql[i-1] == '@') || (i+1 < len(sql) && sql[i+1] == '@') {
+			continue
+		}
+
+		j := i + 1
+		for j < len(sql) && isNameChar(sql[j], j == i+1) {
+			j++
+		}
+		if j == i+1 {
+			continue
+		}
+		name := sql[i+1 : j]
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+		i = j - 1
+	}
+	return names
+}
+
+// isNameChar 是否为参数名字符（首字符不能为数字）
+func isNameChar(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	default:
+		return false
+	}
+}
@@ -0,0 +1,178 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNamedParams(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{name: "unique in order", sql: "SELECT * FROM t WHERE a = @a AND b > @b_2 OR a < @a", want: []string{"a", "b_2"}},
+		{name: "string literal ignored", sql: "SELECT '@not' AS x, @yes", want: []string{"yes"}},
+		{name: "double at ignored", sql: "SELECT @@version, v @@ to_tsquery(@q)", want: []string{"q"}},
+		{name: "digit cannot start name", sql: "SELECT @1, @_x", want: []string{"_x"}},
+		{name: "none", sql: "SELECT 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := namedParams(tt.sql); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("namedParams = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// nameCount 原生查询的扫描目标
+type nameCount struct {
+	Name  string
+	Count int
+}
+
+func TestNativeQuery(t *testing.T) {
+	repo := NewGormRepository[autoEntity, int](openTestDB(t, &autoEntity{}))
+	ctx := context.Background()
+	entities := []*autoEntity{{Name: "a"}, {Name: "b"}, {Name: "b"}, {Name: "c"}, {Name: "c"}, {Name: "c"}}
+	if err := repo.CreateBatch(ctx, entities); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	grouped := NewNativeQuery[nameCount](repo.DB(), `
+		SELECT name, COUNT(*) AS count FROM auto_entities
+		WHERE name <> @skip GROUP BY name ORDER BY count DESC;`)
+
+	if _, err := grouped.Find(ctx); err == nil || !strings.Contains(err.Error(), "skip") {
+		t.Fatalf("missing param err = %v", err)
+	}
+
+	q := grouped.Param("skip", "a")
+	if q == grouped || len(grouped.params) != 0 {
+		t.Fatal("Param modified the original query")
+	}
+
+	items, err := q.Find(ctx)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(items) != 2 || *items[0] != (nameCount{Name: "c", Count: 3}) {
+		t.Fatalf("items = %+v", items)
+	}
+
+	first, err := q.First(ctx)
+	if err != nil || first == nil || first.Name != "c" {
+		t.Fatalf("first = %+v, %v", first, err)
+	}
+	// Params 覆盖已设置的参数
+	all, err := q.Params(map[string]interface{}{"skip": "x"}).Find(ctx)
+	if err != nil || len(all) != 3 {
+		t.Fatalf("find with params = %d, %v", len(all), err)
+	}
+
+	page, err := q.Page(ctx, 2, 1)
+	if err != nil {
+		t.Fatalf("page: %v", err)
+	}
+	if page.Total != 2 || len(page.Items) != 1 || page.Items[0].Name != "b" || page.HasNext() {
+		t.Fatalf("page = total %d items %+v", page.Total, page.Items)
+	}
+
+	affected, err := repo.Native("UPDATE auto_entities SET name = @to WHERE name = @from").
+		Params(map[string]interface{}{"from": "c", "to": "d"}).
+		Exec(ctx)
+	if err != nil || affected != 3 {
+		t.Fatalf("exec = %d, %v", affected, err)
+	}
+}
+
+func TestNativeQueryInTx(t *testing.T) {
+	repo := NewGormRepository[autoEntity, int](openTestDB(t, &autoEntity{}))
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	err := repo.WithTx(ctx, func(ctx context.Context) error {
+		if err := repo.Create(ctx, &autoEntity{Name: "tx"}); err != nil {
+			return err
+		}
+		// 原生查询在上下文事务中执行，能看到未提交的数据
+		count, err := repo.Native("SELECT * FROM auto_entities WHERE name = @name").Param("name", "tx").Count(ctx)
+		if err != nil || count != 1 {
+			t.Errorf("count in tx = %d, %v", count, err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("err = %v", err)
+	}
+	if count, err := repo.Native("SELECT * FROM auto_entities").Count(ctx); err != nil || count != 0 {
+		t.Fatalf("count after rollback = %d, %v", count, err)
+	}
+}
+
+func TestNativeQueryWithoutParams(t *testing.T) {
+	repo := NewGormRepository[autoEntity, int](openTestDB(t, &autoEntity{}))
+	ctx := context.Background()
+	if err := repo.CreateBatch(ctx, []*autoEntity{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// 不含 @name 的 SQL（包括 ? 占位符与 DDL）不绑定参数表
+	tests := []struct {
+		name string
+		run  func() (int64, error)
+		want int64 // 为 -1 时不检查结果（DDL 的影响行数依赖驱动）
+	}{
+		{name: "count", want: 2, run: func() (int64, error) {
+			return repo.Native("SELECT * FROM auto_entities WHERE id > 0").Count(ctx)
+		}},
+		{name: "find", want: 1, run: func() (int64, error) {
+			items, err := repo.Native("SELECT * FROM auto_entities WHERE name = 'a'").Find(ctx)
+			return int64(len(items)), err
+		}},
+		{name: "first", want: 1, run: func() (int64, error) {
+			item, err := repo.Native("SELECT * FROM auto_entities ORDER BY id").First(ctx)
+			if item == nil || item.Name != "a" {
+				return 0, err
+			}
+			return 1, err
+		}},
+		{name: "page", want: 1, run: func() (int64, error) {
+			page, err := repo.Native("SELECT * FROM auto_entities ORDER BY id").Page(ctx, 2, 1)
+			if err != nil {
+				return 0, err
+			}
+			return int64(len(page.Items)), nil
+		}},
+		{name: "exec", want: 2, run: func() (int64, error) {
+			return repo.Native("UPDATE auto_entities SET name = 'x'").Exec(ctx)
+		}},
+		{name: "exec ddl", want: -1, run: func() (int64, error) {
+			return repo.Native("CREATE INDEX idx_native_name ON auto_entities (name)").Exec(ctx)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.run()
+			if err != nil || (tt.want >= 0 && got != tt.want) {
+				t.Fatalf("result = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNativeQueryFS(t *testing.T) {
+	fsys := fstest.MapFS{"queries/names.sql": {Data: []byte("SELECT name FROM auto_entities;\n")}}
+	q, err := NewNativeQueryFS[autoEntity](nil, fsys, "queries/names.sql")
+	if err != nil || q.SQL() != "SELECT name FROM auto_entities" {
+		t.Fatalf("query = %q, %v", q.SQL(), err)
+	}
+	if _, err := NewNativeQueryFS[autoEntity](nil, fsys, "queries/missing.sql"); err == nil {
+		t.Fatal("missing file should fail")
+	}
+}
@@ -0,0 +1,152 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	apperrors "soliton-client/share/errors"
+	"soliton-client/share/patch"
+)
+
+// schema 解析实体的 GORM 模型
+func (r *GormRepository[T, ID]) schema() (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// UpdateFields 按主键只更新指定字段
+// fields 的键可以是结构体字段名或数据库列名；同时自动更新 UpdatedAt 并递增 Version，
+// 不会覆盖其他字段，避免并发请求修改不同字段时相互覆盖
+func (r *GormRepository[T, ID]) UpdateFields(ctx context.Context, id ID, fields map[string]interface{}) error {
+	return r.updateFields(ctx, id, fields, nil)
+}
+
+// updateFields 按主键更新指定字段
+// version 不为 nil 时附加 version = ? 乐观锁条件，版本不一致（实体已被其他请求修改）时返回 Conflict
+func (r *GormRepository[T, ID]) updateFields(ctx context.Context, id ID, fields map[string]interface{}, version interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+	if s.PrioritizedPrimaryField == nil {
+		return fmt.Errorf("entity %s has no primary key", s.Name)
+	}
+
+	columns := make(map[string]interface{}, len(fields)+2)
+	for key, value := range fields {
+		field := s.LookUpField(key)
+		if field == nil || field.DBName == "" {
+			return apperrors.ErrBadRequest(fmt.Sprintf("未知字段: %s", key))
+		}
+		if field.PrimaryKey {
+			return apperrors.ErrBadRequest(fmt.Sprintf("字段 %s 不允许修改", key))
+		}
+		// 按 map 更新时 GORM 不会调用字段的序列化器（如 serializer:json），需手动转换
+		if field.Serializer != nil {
+			if value, err = field.Serializer.Value(ctx, field, reflect.Value{}, value); err != nil {
+				return err
+			}
+		}
+		columns[field.DBName] = value
+	}
+
+	// 审计字段
+	if field := s.LookUpField("UpdatedAt"); field != nil {
+		columns[field.DBName] = time.Now()
+	}
+	versionField := s.LookUpField("Version")
+	if versionField != nil {
+		columns[versionField.DBName] = gorm.Expr(fmt.Sprintf("%s + 1", versionField.DBName))
+	}
+
+	db := r.getDB(ctx).
+		Model(new(T)).
+		Where(clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: s.PrioritizedPrimaryField.DBName},
+			Value:  id,
+		})
+	if version != nil && versionField != nil {
+		db = db.Where(clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: versionField.DBName},
+			Value:  version,
+		})
+	}
+	result := db.Updates(columns)
+	if result.Error != nil {
+		return TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if version != nil && versionField != nil {
+			return apperrors.ErrConflict("数据已被修改，请刷新后重试")
+		}
+		return apperrors.ErrNotFound("资源不存在")
+	}
+	return nil
+}
+
+// Patch 按 JSON Merge Patch（RFC 7396）部分更新实体
+// allowed 为允许修改的 JSON 字段名白名单，只有值发生变化的字段会写入数据库；
+// 读取与更新在同一事务中执行，实体包含 Version 字段时以读取到的版本做乐观锁校验，
+// 并发修改导致版本不一致时返回 Conflict；返回更新后的实体
+func (r *GormRepository[T, ID]) Patch(ctx context.Context, id ID, doc []byte, allowed ...string) (*T, error) {
+	var patched *T
+	err := r.WithTx(ctx, func(ctx context.Context) error {
+		entity, err := r.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if entity == nil {
+			return apperrors.ErrNotFound("资源不存在")
+		}
+		version, err := r.versionOf(ctx, entity)
+		if err != nil {
+			return err
+		}
+
+		changes, err := patch.ApplyMerge(entity, doc, allowed...)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			patched = entity
+			return nil
+		}
+
+		if err := r.updateFields(ctx, id, changes, version); err != nil {
+			return err
+		}
+		patched, err = r.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// versionOf 获取实体的版本号，实体没有 Version 字段时返回 nil
+func (r *GormRepository[T, ID]) versionOf(ctx context.Context, entity *T) (interface{}, error) {
+	s, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	field := s.LookUpField("Version")
+	if field == nil {
+		return nil, nil
+	}
+	version, _ := field.ValueOf(ctx, reflect.ValueOf(entity).Elem())
+	return version, nil
+}
@@ -0,0 +1,142 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// profileEntity 用于部分更新测试的实体
+type profileEntity struct {
+	BaseEntity
+	Name     string            `json:"name"`
+	Nickname string            `json:"nickname"`
+	Age      int               `json:"age"`
+	Settings map[string]string `gorm:"serializer:json" json:"settings"`
+}
+
+func TestPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		patch       string
+		allowed     []string
+		wantCode    int // 期望的错误码，0 表示成功
+		wantVersion int
+		check       func(t *testing.T, e *profileEntity)
+	}{
+		{
+			name:        "changed fields only",
+			patch:       `{"nickname":"bob","age":30}`,
+			allowed:     []string{"nickname", "age"},
+			wantVersion: 2,
+			check: func(t *testing.T, e *profileEntity) {
+				if e.Nickname != "bob" || e.Age != 30 || e.Name != "alice" {
+					t.Errorf("entity = %+v", e)
+				}
+			},
+		},
+		{
+			name:        "null resets to zero",
+			patch:       `{"age":null}`,
+			allowed:     []string{"age"},
+			wantVersion: 2,
+			check: func(t *testing.T, e *profileEntity) {
+				if e.Age != 0 {
+					t.Errorf("age = %d, want 0", e.Age)
+				}
+			},
+		},
+		{
+			name:        "nested merge",
+			patch:       `{"settings":{"lang":"en","theme":null}}`,
+			allowed:     []string{"settings"},
+			wantVersion: 2,
+			check: func(t *testing.T, e *profileEntity) {
+				if len(e.Settings) != 1 || e.Settings["lang"] != "en" {
+					t.Errorf("settings = %v", e.Settings)
+				}
+			},
+		},
+		{name: "unchanged skips write", patch: `{"name":"alice"}`, allowed: []string{"name"}, wantVersion: 1},
+		{name: "field not allowed", patch: `{"name":"eve"}`, allowed: []string{"nickname"}, wantCode: apperrors.BadRequest},
+		{name: "unknown field", patch: `{"email":"x"}`, allowed: []string{"email"}, wantCode: apperrors.BadRequest},
+		{name: "wrong type", patch: `{"age":"old"}`, allowed: []string{"age"}, wantCode: apperrors.BadRequest},
+		{name: "not an object", patch: `[1]`, allowed: []string{"age"}, wantCode: apperrors.BadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewGormRepository[profileEntity, int](openTestDB(t, &profileEntity{}))
+			ctx := context.Background()
+			entity := &profileEntity{Name: "alice", Age: 20, Settings: map[string]string{"theme": "dark"}}
+			if err := repo.Create(ctx, entity); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			got, err := repo.Patch(ctx, entity.ID, []byte(tt.patch), tt.allowed...)
+			if tt.wantCode != 0 {
+				if appErr, ok := apperrors.AsAppError(err); !ok || appErr.Code != tt.wantCode {
+					t.Fatalf("err = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("patch: %v", err)
+			}
+			if got.Version != tt.wantVersion {
+				t.Errorf("version = %d, want %d", got.Version, tt.wantVersion)
+			}
+			if tt.check != nil {
+				tt.check(t, got)
+			}
+		})
+	}
+}
+
+func TestUpdateFieldsOptimisticLock(t *testing.T) {
+	repo := NewGormRepository[profileEntity, int](openTestDB(t, &profileEntity{}))
+	ctx := context.Background()
+	entity := &profileEntity{Name: "alice"}
+	if err := repo.Create(ctx, entity); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// 另一个请求先修改了实体，版本号变为 2
+	if err := repo.UpdateFields(ctx, entity.ID, map[string]interface{}{"Nickname": "first"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		version  interface{}
+		wantCode int
+	}{
+		{name: "stale version", version: 1, wantCode: apperrors.Conflict},
+		{name: "current version", version: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.updateFields(ctx, entity.ID, map[string]interface{}{"nickname": tt.name}, tt.version)
+			if tt.wantCode != 0 {
+				if appErr, ok := apperrors.AsAppError(err); !ok || appErr.Code != tt.wantCode {
+					t.Fatalf("err = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("update: %v", err)
+			}
+		})
+	}
+
+	got, _ := repo.GetByID(ctx, entity.ID)
+	if got.Nickname != "current version" || got.Version != 3 {
+		t.Fatalf("entity = %+v", got)
+	}
+
+	if err := repo.UpdateFields(ctx, 999, map[string]interface{}{"name": "x"}); err == nil {
+		t.Fatal("update of missing entity should fail")
+	} else if appErr, _ := apperrors.AsAppError(err); appErr == nil || appErr.Code != apperrors.NotFound {
+		t.Fatalf("err = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,105 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"soliton-client/share/repository"
+)
+
+// projectionTag 投影字段的计算表达式标签
+const projectionTag = "select"
+
+// FindAs 执行查询并将结果直接扫描到投影类型 D，只查询 D 需要的列
+// D 的字段按列名映射到实体 T 的列（支持 gorm:"column:xxx" 标签，匿名嵌入结构体会展开）；
+// select:"表达式" 标签声明计算列，如 select:"upper(name)"；select:"-" 忽略该字段
+func FindAs[D any, T any](ctx context.Context, b repository.QueryBuilder[T]) ([]*D, error) {
+	db, err := project[D](ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*D
+	if err := db.Find(&items).Error; err != nil {
+		return nil, TranslateError(err)
+	}
+	return items, nil
+}
+
+// FirstAs 执行查询并将第一条结果扫描到投影类型 D，没有结果时返回 nil
+func FirstAs[D any, T any](ctx context.Context, b repository.QueryBuilder[T]) (*D, error) {
+	db, err := project[D](ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*D
+	if err := db.Limit(1).Find(&items).Error; err != nil {
+		return nil, TranslateError(err)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return items[0], nil
+}
+
+// project 在构建器的查询上应用投影列
+func project[D any, T any](ctx context.Context, b repository.QueryBuilder[T]) (*gorm.DB, error) {
+	gb, ok := b.(*GormQueryBuilder[T])
+	if !ok {
+		return nil, fmt.Errorf("projection requires a GORM query builder, got %T", b)
+	}
+
+	columns, err := projectionColumns[D, T](gb.db)
+	if err != nil {
+		return nil, err
+	}
+
+	db := gb.build(ctx).Model(new(T))
+	for _, order := range gb.options.OrderBys {
+		// 按相关度排序时需要同时查询得分列
+		if order.Field == repository.RelevanceField {
+			return selectScore(db, gb.options.Conditions, columns), nil
+		}
+	}
+	return db.Select(strings.Join(columns, ", ")), nil
+}
+
+// projectionColumns 计算投影类型 D 的查询列
+func projectionColumns[D any, T any](db *gorm.DB) ([]string, error) {
+	entity := &gorm.Statement{DB: db}
+	if err := entity.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	dto := &gorm.Statement{DB: db}
+	if err := dto.Parse(new(D)); err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(dto.Schema.Fields))
+	for _, field := range dto.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		expr, hasExpr := field.Tag.Lookup(projectionTag)
+		switch {
+		case expr == "-":
+			continue
+		case hasExpr && expr != "":
+			columns = append(columns, fmt.Sprintf("%s AS %s", expr, dto.Quote(field.DBName)))
+		default:
+			if _, ok := entity.Schema.FieldsByDBName[field.DBName]; !ok {
+				return nil, fmt.Errorf("projection %s: column %s not found in %s", dto.Schema.Name, field.DBName, entity.Schema.Name)
+			}
+			columns = append(columns, dto.Quote(entity.Schema.Table+"."+field.DBName))
+		}
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("projection %s has no columns", dto.Schema.Name)
+	}
+	return columns, nil
+}
@@ -0,0 +1,78 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"soliton-client/share/repository"
+)
+
+// PersonName 嵌入到投影中的字段组（GORM 只展开导出的嵌入结构体）
+type PersonName struct {
+	DisplayName string `gorm:"column:name"`
+}
+
+// personSummary 投影类型：列映射、嵌入展开、计算列与忽略字段
+type personSummary struct {
+	ID int
+	PersonName
+	Upper string `select:"upper(name)"`
+	Note  string `select:"-"`
+}
+
+// badProjection 引用实体中不存在的列
+type badProjection struct {
+	Nickname string
+}
+
+func TestFindAs(t *testing.T) {
+	repo := NewQueryableGormRepository[personEntity, int](openTestDB(t, &personEntity{}))
+	ctx := context.Background()
+	people := []*personEntity{{Name: "alice", Email: "a@example.com"}, {Name: "bob", Email: "b@example.com"}}
+	if err := repo.CreateBatch(ctx, people); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	items, err := FindAs[personSummary](ctx, repo.Query().OrderByDesc("id"))
+	if err != nil {
+		t.Fatalf("find as: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("items = %d, want 2", len(items))
+	}
+	if got := items[0]; got.ID != people[1].ID || got.DisplayName != "bob" || got.Upper != "BOB" || got.Note != "" {
+		t.Errorf("item = %+v", got)
+	}
+
+	tests := []struct {
+		name    string
+		query   repository.QueryBuilder[personEntity]
+		want    string // 期望第一条的 DisplayName，为空表示没有结果
+		wantErr bool
+	}{
+		{name: "first match", query: repo.Query().Where(repository.Eq("email", "a@example.com")), want: "alice"},
+		{name: "no match", query: repo.Query().Where(repository.Eq("name", "zed"))},
+		{name: "relevance order", query: repo.Query().Where(repository.FullText("bob", "name")).OrderByRelevance(), want: "bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FirstAs[personSummary](ctx, tt.query)
+			if err != nil {
+				t.Fatalf("first as: %v", err)
+			}
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("got %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.DisplayName != tt.want {
+				t.Fatalf("got %+v, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := FindAs[badProjection](ctx, repo.Query()); err == nil {
+		t.Fatal("projection with unknown column should fail")
+	}
+}
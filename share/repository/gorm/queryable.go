@@ -2,7 +2,9 @@ package gorm
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
 
@@ -26,7 +28,7 @@ func (r *QueryableGormRepository[T, ID]) Where(ctx context.Context, conditions .
 	db := ApplyConditions(r.getDB(ctx), conditions...)
 	var entities []*T
 	if err := db.Find(&entities).Error; err != nil {
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	return entities, nil
 }
@@ -37,7 +39,7 @@ func (r *QueryableGormRepository[T, ID]) Count(ctx context.Context, conditions .
 	var count int64
 	var entity T
 	if err := db.Model(&entity).Count(&count).Error; err != nil {
-		return 0, err
+		return 0, TranslateError(err)
 	}
 	return count, nil
 }
@@ -80,7 +82,11 @@ func ApplyCondition(db *gorm.DB, cond *repository.Condition) *gorm.DB {
 	case repository.OpLessOrEqual:
 		return db.Where(fmt.Sprintf("%s <= ?", cond.Field), cond.Value)
 	case repository.OpLike:
-		return db.Where(fmt.Sprintf("%s LIKE ?", cond.Field), cond.Value)
+		return db.Where(likeClause(db, cond.Field, false), cond.Value)
+	case repository.OpNotLike:
+		return db.Where(likeClause(db, cond.Field, true), cond.Value)
+	case repository.OpILike:
+		return db.Where(iLikeClause(db, cond.Field), cond.Value)
 	case repository.OpIn:
 		return db.Where(fmt.Sprintf("%s IN ?", cond.Field), cond.Value)
 	case repository.OpNotIn:
@@ -89,13 +95,18 @@ func ApplyCondition(db *gorm.DB, cond *repository.Condition) *gorm.DB {
 		if values, ok := cond.Value.([]interface{}); ok && len(values) == 2 {
 			return db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", cond.Field), values[0], values[1])
 		}
-		return db
+		return withError(db, fmt.Errorf("operator %s requires two values", cond.Operator))
 	case repository.OpIsNull:
 		return db.Where(fmt.Sprintf("%s IS NULL", cond.Field))
 	case repository.OpIsNotNull:
 		return db.Where(fmt.Sprintf("%s IS NOT NULL", cond.Field))
+	case repository.OpJSONPathEqual, repository.OpJSONContains, repository.OpJSONHasKey,
+		repository.OpArrayContains, repository.OpArrayOverlaps:
+		return applyPostgresOperator(db, cond)
+	case repository.OpFullText:
+		return applyFullText(db, cond)
 	default:
-		return db
+		return withError(db, fmt.Errorf("%w: %s", ErrOperatorNotSupported, cond.Operator))
 	}
 }
 
@@ -115,50 +126,110 @@ func NewGormQueryBuilder[T any](db *gorm.DB) *GormQueryBuilder[T] {
 
 // Where 添加查询条件
 func (b *GormQueryBuilder[T]) Where(condition *repository.Condition) repository.QueryBuilder[T] {
-	b.options.AddCondition(condition)
-	return b
+	nb := b.clone()
+	nb.options.AddCondition(condition)
+	return nb
 }
 
 // And 添加 AND 条件
 func (b *GormQueryBuilder[T]) And(conditions ...*repository.Condition) repository.QueryBuilder[T] {
-	b.options.AddConditions(conditions...)
-	return b
+	nb := b.clone()
+	nb.options.AddConditions(conditions...)
+	return nb
 }
 
 // OrderBy 添加排序（升序）
 func (b *GormQueryBuilder[T]) OrderBy(field string) repository.QueryBuilder[T] {
-	b.options.AddOrderBy(field, false)
-	return b
+	nb := b.clone()
+	nb.options.AddOrderBy(field, false)
+	return nb
 }
 
 // OrderByDesc 添加排序（降序）
 func (b *GormQueryBuilder[T]) OrderByDesc(field string) repository.QueryBuilder[T] {
-	b.options.AddOrderBy(field, true)
-	return b
+	nb := b.clone()
+	nb.options.AddOrderBy(field, true)
+	return nb
+}
+
+// OrderByRelevance 按全文检索条件的相关度降序排序
+func (b *GormQueryBuilder[T]) OrderByRelevance() repository.QueryBuilder[T] {
+	nb := b.clone()
+	nb.options.AddOrderBy(repository.RelevanceField, true)
+	return nb
 }
 
 // Limit 限制返回数量
 func (b *GormQueryBuilder[T]) Limit(limit int) repository.QueryBuilder[T] {
-	b.options.SetLimit(limit)
-	return b
+	nb := b.clone()
+	nb.options.SetLimit(limit)
+	return nb
 }
 
 // Offset 设置偏移量
 func (b *GormQueryBuilder[T]) Offset(offset int) repository.QueryBuilder[T] {
-	b.options.SetOffset(offset)
-	return b
+	nb := b.clone()
+	nb.options.SetOffset(offset)
+	return nb
 }
 
 // Select 指定查询字段
 func (b *GormQueryBuilder[T]) Select(fields ...string) repository.QueryBuilder[T] {
-	b.options.SetFields(fields...)
-	return b
+	nb := b.clone()
+	nb.options.SetFields(fields...)
+	return nb
+}
+
+// ForUpdate 加排他行锁（SELECT ... FOR UPDATE），必须在事务中使用
+func (b *GormQueryBuilder[T]) ForUpdate() repository.QueryBuilder[T] {
+	nb := b.clone()
+	nb.options.SetLock(repository.LockForUpdate)
+	return nb
+}
+
+// ForShare 加共享行锁（SELECT ... FOR SHARE），必须在事务中使用
+func (b *GormQueryBuilder[T]) ForShare() repository.QueryBuilder[T] {
+	nb := b.clone()
+	nb.options.SetLock(repository.LockForShare)
+	return nb
+}
+
+// NoWait 行锁被占用时立即报错而不是等待
+func (b *GormQueryBuilder[T]) NoWait() repository.QueryBuilder[T] {
+	nb := b.clone()
+	nb.options.SetLockWait(repository.LockNoWait)
+	return nb
+}
+
+// SkipLocked 跳过已被锁定的行
+func (b *GormQueryBuilder[T]) SkipLocked() repository.QueryBuilder[T] {
+	nb := b.clone()
+	nb.options.SetLockWait(repository.LockSkipLocked)
+	return nb
+}
+
+// Clone 复制构建器
+func (b *GormQueryBuilder[T]) Clone() repository.QueryBuilder[T] {
+	return b.clone()
+}
+
+// clone 复制构建器（返回具体类型）
+func (b *GormQueryBuilder[T]) clone() *GormQueryBuilder[T] {
+	return &GormQueryBuilder[T]{
+		db:      b.db,
+		options: b.options.Clone(),
+	}
 }
 
 // build 构建 GORM 查询
+// 上下文中存在事务时在该事务中执行
 func (b *GormQueryBuilder[T]) build(ctx context.Context) *gorm.DB {
-	db := b.db.WithContext(ctx)
+	_, inTx := txFromContext(ctx)
+	return b.apply(dbFromContext(ctx, b.db), inTx)
+}
 
+// apply 将查询选项应用到 GORM 查询
+func (b *GormQueryBuilder[T]) apply(db *gorm.DB, inTx bool) *gorm.DB {
 	// 应用查询条件
 	for _, cond := range b.options.Conditions {
 		db = ApplyCondition(db, cond)
@@ -170,13 +241,7 @@ func (b *GormQueryBuilder[T]) build(ctx context.Context) *gorm.DB {
 	}
 
 	// 应用排序
-	for _, order := range b.options.OrderBys {
-		if order.Desc {
-			db = db.Order(order.Field + " DESC")
-		} else {
-			db = db.Order(order.Field + " ASC")
-		}
-	}
+	db = applyOrders(db, b.options.OrderBys, b.options.Conditions, b.options.Fields)
 
 	// 应用分页
 	if b.options.LimitVal > 0 {
@@ -186,14 +251,82 @@ func (b *GormQueryBuilder[T]) build(ctx context.Context) *gorm.DB {
 		db = db.Offset(b.options.OffsetVal)
 	}
 
+	// 应用行锁
+	db = applyLock(db, inTx, b.options.Lock, b.options.LockWait)
+
 	return db
 }
 
+// statement 以 DryRun 模式生成查询语句
+func (b *GormQueryBuilder[T]) statement() (*gorm.Statement, error) {
+	var entities []*T
+	// 预览时视为在事务中，以便展示行锁子句
+	db := b.apply(b.db.Session(&gorm.Session{DryRun: true}), true).Find(&entities)
+	if db.Error != nil {
+		return nil, db.Error
+	}
+	return db.Statement, nil
+}
+
+// ToSQL 生成查询语句（参数已内联），用于调试与测试断言，不访问数据库
+func (b *GormQueryBuilder[T]) ToSQL() (string, error) {
+	stmt, err := b.statement()
+	if err != nil {
+		return "", err
+	}
+	return b.db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...), nil
+}
+
+// Explain 返回数据库的执行计划，每行一条计划记录
+func (b *GormQueryBuilder[T]) Explain(ctx context.Context) (string, error) {
+	stmt, err := b.statement()
+	if err != nil {
+		return "", err
+	}
+
+	prefix := "EXPLAIN "
+	if dialectOf(b.db) == SQLite {
+		prefix = "EXPLAIN QUERY PLAN "
+	}
+
+	rows, err := dbFromContext(ctx, b.db).Raw(prefix+stmt.SQL.String(), stmt.Vars...).Rows()
+	if err != nil {
+		return "", TranslateError(err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return "", err
+		}
+		parts := make([]string, 0, len(values))
+		for _, v := range values {
+			parts = append(parts, v.String)
+		}
+		lines = append(lines, strings.Join(parts, " | "))
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
 // Find 执行查询，返回结果列表
 func (b *GormQueryBuilder[T]) Find(ctx context.Context) ([]*T, error) {
 	var entities []*T
 	if err := b.build(ctx).Find(&entities).Error; err != nil {
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	return entities, nil
 }
@@ -205,7 +338,7 @@ func (b *GormQueryBuilder[T]) First(ctx context.Context) (*T, error) {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	return &entity, nil
 }
@@ -214,7 +347,7 @@ func (b *GormQueryBuilder[T]) First(ctx context.Context) (*T, error) {
 func (b *GormQueryBuilder[T]) Count(ctx context.Context) (int64, error) {
 	var count int64
 	var entity T
-	db := b.db.WithContext(ctx)
+	db := dbFromContext(ctx, b.db)
 
 	// 只应用查询条件
 	for _, cond := range b.options.Conditions {
@@ -222,7 +355,7 @@ func (b *GormQueryBuilder[T]) Count(ctx context.Context) (int64, error) {
 	}
 
 	if err := db.Model(&entity).Count(&count).Error; err != nil {
-		return 0, err
+		return 0, TranslateError(err)
 	}
 	return count, nil
 }
@@ -244,16 +377,25 @@ func (b *GormQueryBuilder[T]) Page(ctx context.Context, page, size int) (*reposi
 		return nil, err
 	}
 
-	// 设置分页参数
-	b.options.SetOffset((page - 1) * size)
-	b.options.SetLimit(size)
+	// 查询数据（在副本上设置分页参数，不修改当前构建器）
+	paged := b.clone()
+	paged.options.SetOffset((page - 1) * size).SetLimit(size)
+
+	// 包含全文检索条件时同时返回相关度得分
+	if hasFullText(paged.options.Conditions) {
+		entities, scores, err := findScored[T](paged.build(ctx), paged.options.Conditions, paged.options.Fields)
+		if err != nil {
+			return nil, err
+		}
+		result := repository.NewPageResult(entities, total, page, size)
+		result.Scores = scores
+		return result, nil
+	}
 
-	// 查询数据
-	entities, err := b.Find(ctx)
+	entities, err := paged.Find(ctx)
 	if err != nil {
 		return nil, err
 	}
-
 	return repository.NewPageResult(entities, total, page, size), nil
 }
 
@@ -0,0 +1,90 @@
+package gorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"soliton-client/share/repository"
+)
+
+func TestQueryBuilderImmutable(t *testing.T) {
+	base := NewGormQueryBuilder[autoEntity](dryRunDB(t, PostgreSQL)).
+		Where(repository.Eq("name", "a"))
+	limited := base.Limit(10)
+	ordered := base.OrderByDesc("id")
+	clone := base.Clone().And(repository.Gt("id", 5))
+
+	tests := []struct {
+		name    string
+		builder repository.QueryBuilder[autoEntity]
+		want    string
+	}{
+		{
+			name:    "base unchanged",
+			builder: base,
+			want:    `SELECT * FROM "auto_entities" WHERE name = 'a' AND "auto_entities"."deleted_at" IS NULL`,
+		},
+		{
+			name:    "limit branch",
+			builder: limited,
+			want:    `SELECT * FROM "auto_entities" WHERE name = 'a' AND "auto_entities"."deleted_at" IS NULL LIMIT 10`,
+		},
+		{
+			name:    "order branch",
+			builder: ordered,
+			want:    `SELECT * FROM "auto_entities" WHERE name = 'a' AND "auto_entities"."deleted_at" IS NULL ORDER BY id DESC`,
+		},
+		{
+			name:    "clone with extra condition",
+			builder: clone,
+			want:    `SELECT * FROM "auto_entities" WHERE name = 'a' AND id > 5 AND "auto_entities"."deleted_at" IS NULL`,
+		},
+		{
+			name:    "lock previewed",
+			builder: base.ForUpdate().SkipLocked(),
+			want:    `SELECT * FROM "auto_entities" WHERE name = 'a' AND "auto_entities"."deleted_at" IS NULL FOR UPDATE SKIP LOCKED`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.ToSQL()
+			if err != nil {
+				t.Fatalf("ToSQL: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ToSQL =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryBuilderExecute(t *testing.T) {
+	repo := NewQueryableGormRepository[autoEntity, int](openTestDB(t, &autoEntity{}))
+	ctx := context.Background()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := repo.Create(ctx, &autoEntity{Name: name}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	base := NewGormQueryBuilder[autoEntity](repo.DB()).Where(repository.NotEq("name", "b")).(*GormQueryBuilder[autoEntity])
+	page, err := base.OrderByDesc("id").(*GormQueryBuilder[autoEntity]).Page(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("page: %v", err)
+	}
+	if page.Total != 2 || len(page.Items) != 1 || page.Items[0].Name != "c" {
+		t.Fatalf("page = total %d items %v", page.Total, page.Items)
+	}
+
+	// Page 不修改构建器的分页参数
+	all, err := base.Find(ctx)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("find after page = %d, %v", len(all), err)
+	}
+
+	plan, err := base.Explain(ctx)
+	if err != nil || !strings.Contains(plan, "auto_entities") {
+		t.Fatalf("explain = %q, %v", plan, err)
+	}
+}
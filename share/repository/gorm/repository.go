@@ -2,6 +2,7 @@ package gorm
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 
 	"gorm.io/gorm"
@@ -12,6 +13,9 @@ import (
 // 事务上下文键
 type txKey struct{}
 
+// 事务选项上下文键
+type txOptionsKey struct{}
+
 // GormRepository 基于 GORM 的通用仓储实现
 type GormRepository[T any, ID comparable] struct {
 	db *gorm.DB
@@ -29,25 +33,39 @@ func (r *GormRepository[T, ID]) DB() *gorm.DB {
 	return r.db
 }
 
+// DBContext 获取参与上下文事务的 GORM DB 实例
+// 用于仓储方法无法覆盖的场景（如 ON CONFLICT、批量更新），保证与业务事务一致
+func (r *GormRepository[T, ID]) DBContext(ctx context.Context) *gorm.DB {
+	return r.getDB(ctx)
+}
+
 // getDB 获取数据库连接（支持事务）
 func (r *GormRepository[T, ID]) getDB(ctx context.Context) *gorm.DB {
-	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+	return dbFromContext(ctx, r.db)
+}
+
+// txFromContext 获取上下文中的事务
+func txFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok
+}
+
+// dbFromContext 优先使用上下文中的事务，否则使用 db
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := txFromContext(ctx); ok {
 		return tx
 	}
-	return r.db.WithContext(ctx)
+	return db.WithContext(ctx)
 }
 
 // Create 创建单个实体
 func (r *GormRepository[T, ID]) Create(ctx context.Context, entity *T) error {
-	return r.getDB(ctx).Create(entity).Error
+	return TranslateError(r.getDB(ctx).Create(entity).Error)
 }
 
-// CreateBatch 批量创建实体
+// CreateBatch 批量创建实体（按 DefaultBatchSize 分批写入）
 func (r *GormRepository[T, ID]) CreateBatch(ctx context.Context, entities []*T) error {
-	if len(entities) == 0 {
-		return nil
-	}
-	return r.getDB(ctx).Create(entities).Error
+	return r.CreateBatchWithOptions(ctx, entities, nil)
 }
 
 // GetByID 根据主键查询
@@ -58,20 +76,39 @@ func (r *GormRepository[T, ID]) GetByID(ctx context.Context, id ID) (*T, error)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
-		return nil, err
+		return nil, TranslateError(err)
+	}
+	return &entity, nil
+}
+
+// GetByIDForUpdate 根据主键查询并加排他行锁（SELECT ... FOR UPDATE）
+// 必须在事务中调用，锁在事务结束时释放
+func (r *GormRepository[T, ID]) GetByIDForUpdate(ctx context.Context, id ID) (*T, error) {
+	tx, ok := txFromContext(ctx)
+	if !ok {
+		return nil, ErrLockOutsideTx
+	}
+
+	var entity T
+	db := applyLock(tx, true, repository.LockForUpdate, repository.LockWaitDefault)
+	if err := db.First(&entity, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, TranslateError(err)
 	}
 	return &entity, nil
 }
 
 // Update 更新实体
 func (r *GormRepository[T, ID]) Update(ctx context.Context, entity *T) error {
-	return r.getDB(ctx).Save(entity).Error
+	return TranslateError(r.getDB(ctx).Save(entity).Error)
 }
 
 // Delete 删除实体（逻辑删除）
 func (r *GormRepository[T, ID]) Delete(ctx context.Context, id ID) error {
 	var entity T
-	return r.getDB(ctx).Delete(&entity, id).Error
+	return TranslateError(r.getDB(ctx).Delete(&entity, id).Error)
 }
 
 // List 查询全部列表
@@ -79,14 +116,25 @@ func (r *GormRepository[T, ID]) List(ctx context.Context) ([]*T, error) {
 	var entities []*T
 	err := r.getDB(ctx).Find(&entities).Error
 	if err != nil {
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	return entities, nil
 }
 
 // Page 分页查询
+// 按 request.CountMode 精确统计、估算或跳过总数；后两者多查询一条记录以判断是否有下一页
 func (r *GormRepository[T, ID]) Page(ctx context.Context, request *repository.PageRequest) (*repository.PageResult[*T], error) {
 	db := r.getDB(ctx)
+	mode := countModeOf(db, request.CountMode)
+
+	// 估算总数（在原始查询上执行，不影响后续查询）
+	var estimate int64
+	if mode == repository.CountEstimated {
+		var err error
+		if estimate, err = estimateCount[T](db, request.Conditions); err != nil {
+			return nil, err
+		}
+	}
 
 	// 应用查询条件
 	if len(request.Conditions) > 0 {
@@ -95,44 +143,83 @@ func (r *GormRepository[T, ID]) Page(ctx context.Context, request *repository.Pa
 
 	// 统计总数
 	var total int64
-	var entity T
-	if err := db.Model(&entity).Count(&total).Error; err != nil {
-		return nil, err
+	if mode == repository.CountExact {
+		var entity T
+		if err := db.Model(&entity).Count(&total).Error; err != nil {
+			return nil, TranslateError(err)
+		}
 	}
 
 	// 应用排序
-	for _, order := range request.OrderBy {
-		if order.Desc {
-			db = db.Order(order.Field + " DESC")
-		} else {
-			db = db.Order(order.Field + " ASC")
-		}
+	db = applyOrders(db, request.OrderBy, request.Conditions, nil)
+
+	// 应用分页（不精确统计时多查询一条）
+	limit := request.Size
+	if mode != repository.CountExact {
+		limit++
 	}
+	db = db.Offset(request.Offset()).Limit(limit)
 
-	// 应用分页
-	db = db.Offset(request.Offset()).Limit(request.Size)
+	// 查询数据（包含全文检索条件时同时返回相关度得分）
+	var (
+		entities []*T
+		scores   []float64
+	)
+	if hasFullText(request.Conditions) {
+		var err error
+		if entities, scores, err = findScored[T](db, request.Conditions, nil); err != nil {
+			return nil, err
+		}
+	} else if err := db.Find(&entities).Error; err != nil {
+		return nil, TranslateError(err)
+	}
 
-	// 查询数据
-	var entities []*T
-	if err := db.Find(&entities).Error; err != nil {
-		return nil, err
+	hasMore := len(entities) > request.Size
+	if hasMore {
+		entities = entities[:request.Size]
+		if scores != nil {
+			scores = scores[:request.Size]
+		}
 	}
 
-	return repository.NewPageResult(entities, total, request.Page, request.Size), nil
+	var result *repository.PageResult[*T]
+	switch mode {
+	case repository.CountNone:
+		result = repository.NewUncountedPageResult(entities, request.Page, request.Size, hasMore)
+	case repository.CountEstimated:
+		result = repository.NewEstimatedPageResult(entities, estimate, request.Page, request.Size, hasMore)
+	default:
+		result = repository.NewPageResult(entities, total, request.Page, request.Size)
+	}
+	result.Scores = scores
+	return result, nil
 }
 
 // BeginTx 开启事务
 func (r *GormRepository[T, ID]) BeginTx(ctx context.Context) (context.Context, error) {
-	tx := r.db.WithContext(ctx).Begin()
+	return r.BeginTxWithOptions(ctx, nil)
+}
+
+// BeginTxWithOptions 使用指定的隔离级别开启事务
+func (r *GormRepository[T, ID]) BeginTxWithOptions(ctx context.Context, opts *sql.TxOptions) (context.Context, error) {
+	var tx *gorm.DB
+	if opts != nil {
+		tx = r.db.WithContext(ctx).Begin(opts)
+	} else {
+		tx = r.db.WithContext(ctx).Begin()
+	}
 	if tx.Error != nil {
 		return ctx, tx.Error
 	}
+	if opts != nil {
+		ctx = context.WithValue(ctx, txOptionsKey{}, *opts)
+	}
 	return context.WithValue(ctx, txKey{}, tx), nil
 }
 
 // Commit 提交事务
 func (r *GormRepository[T, ID]) Commit(ctx context.Context) error {
-	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+	if tx, ok := txFromContext(ctx); ok {
 		return tx.Commit().Error
 	}
 	return errors.New("no transaction in context")
@@ -140,15 +227,60 @@ func (r *GormRepository[T, ID]) Commit(ctx context.Context) error {
 
 // Rollback 回滚事务
 func (r *GormRepository[T, ID]) Rollback(ctx context.Context) error {
-	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+	if tx, ok := txFromContext(ctx); ok {
 		return tx.Rollback().Error
 	}
 	return errors.New("no transaction in context")
 }
 
 // WithTx 在事务中执行操作
+// 使用默认事务选项，遇到死锁或序列化冲突时自动重试
 func (r *GormRepository[T, ID]) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
-	txCtx, err := r.BeginTx(ctx)
+	return r.WithTxOptions(ctx, DefaultTxOptions(), fn)
+}
+
+// WithTxOptions 使用指定选项在事务中执行操作
+// 上下文中已存在事务时直接加入该事务，由最外层事务负责重试，
+// 指定的隔离级别或只读属性与外层事务冲突时返回 ErrTxOptionsConflict；
+// 重试会重新执行 fn，fn 中不应包含无法重复执行的外部副作用
+func (r *GormRepository[T, ID]) WithTxOptions(ctx context.Context, opts *TxOptions, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		if err := checkJoinTx(ctx, opts); err != nil {
+			return err
+		}
+		return fn(ctx)
+	}
+	if opts == nil {
+		opts = DefaultTxOptions()
+	}
+	metrics := opts.Metrics
+	if metrics == nil {
+		metrics = DefaultTxStats
+	}
+
+	dbType := dialectOf(r.db)
+	for attempt := 1; ; attempt++ {
+		err := r.runTx(ctx, opts, fn)
+		if err == nil || !IsRetryableError(dbType, err) {
+			return err
+		}
+		if attempt >= opts.MaxAttempts {
+			if opts.MaxAttempts > 1 {
+				metrics.OnExhausted(attempt, err)
+			}
+			return err
+		}
+
+		metrics.OnRetry(attempt+1, err)
+		if sleepErr := sleepContext(ctx, opts.backoff(attempt)); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// runTx 执行一次事务尝试
+func (r *GormRepository[T, ID]) runTx(ctx context.Context, opts *TxOptions, fn func(ctx context.Context) error) error {
+	txCtx, err := r.BeginTxWithOptions(ctx, opts.sqlOptions())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,231 @@
+package gorm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"soliton-client/share/repository"
+)
+
+// defaultSearchLanguage 默认文本检索配置（不做词干处理，适合姓名、邮箱等字段）
+const defaultSearchLanguage = "simple"
+
+// scoreColumn 相关度得分的查询列名
+const scoreColumn = "search_score"
+
+// searchLanguagePattern 文本检索配置名称格式
+var searchLanguagePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// scoredRow 带相关度得分的查询结果
+type scoredRow[T any] struct {
+	Entity T       `gorm:"embedded"`
+	Score  float64 `gorm:"column:search_score"`
+}
+
+// fullTextQueryOf 提取全文检索条件的值
+func fullTextQueryOf(cond *repository.Condition) (repository.FullTextQuery, bool) {
+	if cond == nil || cond.Operator != repository.OpFullText {
+		return repository.FullTextQuery{}, false
+	}
+	query, ok := cond.Value.(repository.FullTextQuery)
+	return query, ok
+}
+
+// hasFullText 条件中是否包含全文检索
+func hasFullText(conditions []*repository.Condition) bool {
+	for _, cond := range conditions {
+		if _, ok := fullTextQueryOf(cond); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFullText 应用全文检索条件，检索词为空时不过滤
+func applyFullText(db *gorm.DB, cond *repository.Condition) *gorm.DB {
+	query, ok := fullTextQueryOf(cond)
+	if !ok {
+		return withError(db, fmt.Errorf("operator %s requires repository.FullTextQuery value", cond.Operator))
+	}
+	if strings.TrimSpace(query.Query) == "" {
+		return db
+	}
+	if query.Vector == "" && len(query.Fields) == 0 {
+		return withError(db, fmt.Errorf("operator %s requires at least one field", cond.Operator))
+	}
+
+	if dialectOf(db) == PostgreSQL {
+		return db.Where(fmt.Sprintf("%s @@ websearch_to_tsquery(?::regconfig, ?)", tsVector(query)), searchLanguage(query), query.Query)
+	}
+
+	// 回退：每个词至少匹配一个字段
+	for _, term := range strings.Fields(query.Query) {
+		ors := make([]string, len(query.Fields))
+		vars := make([]interface{}, len(query.Fields))
+		for i, field := range query.Fields {
+			ors[i] = iLikeClause(db, field)
+			vars[i] = "%" + repository.EscapeLike(term) + "%"
+		}
+		db = db.Where("("+strings.Join(ors, " OR ")+")", vars...)
+	}
+	return db
+}
+
+// relevance 计算全文检索条件的相关度表达式（多个条件时求和）
+// PostgreSQL 使用 ts_rank，其他数据库按命中的词与字段数量计分
+func relevance(db *gorm.DB, conditions []*repository.Condition) (clause.Expr, bool) {
+	var (
+		parts []string
+		vars  []interface{}
+	)
+	for _, cond := range conditions {
+		query, ok := fullTextQueryOf(cond)
+		if !ok || strings.TrimSpace(query.Query) == "" {
+			continue
+		}
+
+		if dialectOf(db) == PostgreSQL {
+			parts = append(parts, fmt.Sprintf("ts_rank(%s, websearch_to_tsquery(?::regconfig, ?))", tsVector(query)))
+			vars = append(vars, searchLanguage(query), query.Query)
+			continue
+		}
+		for _, term := range strings.Fields(query.Query) {
+			for _, field := range query.Fields {
+				parts = append(parts, fmt.Sprintf("CASE WHEN %s THEN 1 ELSE 0 END", iLikeClause(db, field)))
+				vars = append(vars, "%"+repository.EscapeLike(term)+"%")
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return clause.Expr{SQL: "0"}, false
+	}
+	return clause.Expr{SQL: "(" + strings.Join(parts, " + ") + ")", Vars: vars}, true
+}
+
+// applyOrders 应用排序规则
+// RelevanceField 按全文检索相关度排序：查询附加得分列并按该列排序
+// （GORM 的表达式排序无法与普通列排序合并，因此使用列别名）
+func applyOrders(db *gorm.DB, orders []repository.OrderBy, conditions []*repository.Condition, fields []string) *gorm.DB {
+	scored := false
+	for _, order := range orders {
+		direction := " ASC"
+		if order.Desc {
+			direction = " DESC"
+		}
+
+		if order.Field != repository.RelevanceField {
+			db = db.Order(order.Field + direction)
+			continue
+		}
+		if _, ok := relevance(db, conditions); ok {
+			db = db.Order(scoreColumn + direction)
+			scored = true
+		}
+	}
+	if scored {
+		db = selectScore(db, conditions, fields)
+	}
+	return db
+}
+
+// selectScore 查询字段附加相关度得分列，fields 为空时查询全部字段
+func selectScore(db *gorm.DB, conditions []*repository.Condition, fields []string) *gorm.DB {
+	expr, _ := relevance(db, conditions)
+	columns := "*"
+	if len(fields) > 0 {
+		columns = strings.Join(fields, ", ")
+	}
+	return db.Select(fmt.Sprintf("%s, %s AS %s", columns, expr.SQL, scoreColumn), expr.Vars...)
+}
+
+// findScored 查询实体及其相关度得分
+func findScored[T any](db *gorm.DB, conditions []*repository.Condition, fields []string) ([]*T, []float64, error) {
+	var rows []scoredRow[T]
+	if err := selectScore(db.Model(new(T)), conditions, fields).Find(&rows).Error; err != nil {
+		return nil, nil, TranslateError(err)
+	}
+
+	entities := make([]*T, len(rows))
+	scores := make([]float64, len(rows))
+	for i := range rows {
+		entities[i] = &rows[i].Entity
+		scores[i] = rows[i].Score
+	}
+	return entities, scores, nil
+}
+
+// tsVector 检索使用的 tsvector 表达式
+func tsVector(query repository.FullTextQuery) string {
+	if query.Vector != "" {
+		return query.Vector
+	}
+	return fmt.Sprintf("to_tsvector(%s::regconfig, %s)", quoteLiteral(searchLanguage(query)), concatFields(query.Fields))
+}
+
+// concatFields 拼接字段文本（使用 || 而非 concat_ws，以便用于生成列）
+func concatFields(fields []string) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = fmt.Sprintf("coalesce(%s, '')", field)
+	}
+	return strings.Join(parts, " || ' ' || ")
+}
+
+// searchLanguage 文本检索配置
+func searchLanguage(query repository.FullTextQuery) string {
+	if query.Language == "" {
+		return defaultSearchLanguage
+	}
+	return query.Language
+}
+
+// quoteLiteral 转义 SQL 字符串字面量
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// EnsureFullTextIndex 为 PostgreSQL 表创建全文检索生成列与 GIN 索引（幂等）
+// 生成列由 fields 拼接后按 language 分词，查询时通过 repository.FullTextVector 引用该列；
+// 其他数据库回退为 LIKE 匹配，不做任何处理
+func EnsureFullTextIndex(db *gorm.DB, model interface{}, column string, language string, fields ...string) error {
+	if dialectOf(db) != PostgreSQL {
+		return nil
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("full-text index requires at least one field")
+	}
+	if language == "" {
+		language = defaultSearchLanguage
+	}
+	if !searchLanguagePattern.MatchString(language) {
+		return fmt.Errorf("invalid text search configuration: %q", language)
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	table := stmt.Quote(stmt.Schema.Table)
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = stmt.Quote(field)
+	}
+
+	vector := fmt.Sprintf("to_tsvector(%s::regconfig, %s)", quoteLiteral(language), concatFields(quoted))
+	ddl := []string{
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s tsvector GENERATED ALWAYS AS (%s) STORED",
+			table, stmt.Quote(column), vector),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (%s)",
+			stmt.Quote(fmt.Sprintf("idx_%s_%s", stmt.Schema.Table, column)), table, stmt.Quote(column)),
+	}
+	for _, sql := range ddl {
+		if err := db.Exec(sql).Error; err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package gorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"soliton-client/share/repository"
+)
+
+// personEntity 全文检索测试实体
+type personEntity struct {
+	BaseEntity
+	Name  string
+	Email string
+}
+
+func TestFullTextSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		cond    *repository.Condition
+		wantSQL string // 期望包含的 SQL 片段，为空表示不过滤
+		wantErr bool
+	}{
+		{
+			name:    "computed vector",
+			cond:    repository.FullText("alice -bob", "name", "email"),
+			wantSQL: `WHERE to_tsvector('simple'::regconfig, coalesce(name, '') || ' ' || coalesce(email, '')) @@ websearch_to_tsquery('simple'::regconfig, 'alice -bob')`,
+		},
+		{
+			name:    "stored vector",
+			cond:    repository.FullTextVector("search_vector", "alice", "name"),
+			wantSQL: `WHERE search_vector @@ websearch_to_tsquery('simple'::regconfig, 'alice')`,
+		},
+		{
+			name: "language",
+			cond: repository.NewCondition("name", repository.OpFullText,
+				repository.FullTextQuery{Query: "running", Fields: []string{"name"}, Language: "english"}),
+			wantSQL: `to_tsvector('english'::regconfig, coalesce(name, '')) @@ websearch_to_tsquery('english'::regconfig, 'running')`,
+		},
+		{name: "blank query", cond: repository.FullText("  ", "name")},
+		{name: "no fields", cond: repository.FullText("alice"), wantErr: true},
+		{name: "wrong value", cond: repository.NewCondition("name", repository.OpFullText, "alice"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dryRunSQL(ApplyCondition(dryRunDB(t, PostgreSQL), tt.cond))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("sql = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if tt.wantSQL == "" {
+				if strings.Contains(got, "@@") {
+					t.Errorf("sql = %q, want no full-text filter", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.wantSQL) {
+				t.Errorf("sql = %q\nwant %q", got, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestOrderByRelevanceSQL(t *testing.T) {
+	builder := NewGormQueryBuilder[autoEntity](dryRunDB(t, PostgreSQL)).
+		Where(repository.FullText("alice", "name")).
+		OrderByRelevance().
+		OrderBy("id")
+	got, err := builder.ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL: %v", err)
+	}
+	for _, want := range []string{
+		`SELECT *, (ts_rank(to_tsvector('simple'::regconfig, coalesce(name, '')), websearch_to_tsquery('simple'::regconfig, 'alice'))) AS search_score`,
+		`ORDER BY search_score DESC,id ASC`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("sql = %q\nwant %q", got, want)
+		}
+	}
+
+	// 没有全文检索条件时忽略相关度排序
+	plain, err := NewGormQueryBuilder[autoEntity](dryRunDB(t, PostgreSQL)).OrderByRelevance().ToSQL()
+	if err != nil || strings.Contains(plain, "search_score") {
+		t.Fatalf("sql = %q, %v", plain, err)
+	}
+}
+
+func TestFullTextFallback(t *testing.T) {
+	repo := NewQueryableGormRepository[personEntity, int](openTestDB(t, &personEntity{}))
+	ctx := context.Background()
+	people := []*personEntity{
+		{Name: "Alice Smith", Email: "alice@example.com"},
+		{Name: "Bob Smith", Email: "bob@example.com"},
+		{Name: "Carol", Email: "smith_carol@example.com"},
+		{Name: "100% Dave", Email: "dave@example.com"},
+	}
+	if err := repo.CreateBatch(ctx, people); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantNames  []string // 按相关度降序
+		wantScores []float64
+	}{
+		{name: "every term must match", query: "alice SMITH", wantNames: []string{"Alice Smith"}, wantScores: []float64{3}},
+		{name: "ranked by hits", query: "smith", wantNames: []string{"Alice Smith", "Bob Smith", "Carol"}, wantScores: []float64{1, 1, 1}},
+		{name: "wildcards literal", query: "100%", wantNames: []string{"100% Dave"}, wantScores: []float64{1}},
+		{name: "underscore literal", query: "h_c", wantNames: []string{"Carol"}, wantScores: []float64{1}},
+		{name: "no match", query: "zed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := repository.NewPageRequest(1, 10).
+				WithCondition(repository.FullText(tt.query, "name", "email")).
+				WithRelevanceOrder().
+				WithOrderBy("id", false)
+			page, err := repo.Page(ctx, request)
+			if err != nil {
+				t.Fatalf("page: %v", err)
+			}
+			if len(page.Items) != len(tt.wantNames) || int(page.Total) != len(tt.wantNames) {
+				t.Fatalf("items = %d, total = %d, want %d", len(page.Items), page.Total, len(tt.wantNames))
+			}
+			for i, item := range page.Items {
+				if item.Name != tt.wantNames[i] || page.Scores[i] != tt.wantScores[i] {
+					t.Errorf("item %d = %s (%v), want %s (%v)", i, item.Name, page.Scores[i], tt.wantNames[i], tt.wantScores[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEnsureFullTextIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   DatabaseType
+		language string
+		fields   []string
+		wantErr  bool
+	}{
+		{name: "non postgres no-op", dbType: SQLite},
+		{name: "postgres", dbType: PostgreSQL, fields: []string{"name", "email"}},
+		{name: "no fields", dbType: PostgreSQL, wantErr: true},
+		{name: "invalid language", dbType: PostgreSQL, language: "english'; DROP TABLE x; --", fields: []string{"name"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EnsureFullTextIndex(dryRunDB(t, tt.dbType), &personEntity{}, "search_vector", tt.language, tt.fields...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrTxOptionsConflict 加入外层事务时指定的事务选项与外层事务冲突
+var ErrTxOptionsConflict = errors.New("transaction options conflict with the active transaction")
+
+// TxOptions 事务选项
+// 上下文中已存在事务时 WithTxOptions 加入外层事务：Isolation 与 ReadOnly 须与外层事务兼容，
+// 重试相关选项（MaxAttempts、BaseBackoff、MaxBackoff、Metrics）不生效，由外层事务决定
+type TxOptions struct {
+	Isolation   sql.IsolationLevel // 隔离级别（默认使用数据库默认级别）
+	ReadOnly    bool               // 是否只读事务
+	MaxAttempts int                // 最大尝试次数（含首次），小于等于 1 表示不重试
+	BaseBackoff time.Duration      // 首次重试的退避时间
+	MaxBackoff  time.Duration      // 退避时间上限
+	Metrics     TxMetrics          // 重试指标，为空时使用 DefaultTxStats
+}
+
+// DefaultTxOptions 默认事务选项
+func DefaultTxOptions() *TxOptions {
+	return &TxOptions{
+		Isolation:   sql.LevelDefault,
+		MaxAttempts: 3,
+		BaseBackoff: 20 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+}
+
+// sqlOptions 转换为 database/sql 事务选项
+func (o *TxOptions) sqlOptions() *sql.TxOptions {
+	return &sql.TxOptions{
+		Isolation: o.Isolation,
+		ReadOnly:  o.ReadOnly,
+	}
+}
+
+// checkJoinTx 检查加入上下文中的事务时选项是否兼容
+// 指定了与外层事务不同的隔离级别，或在只读事务中请求读写事务时视为冲突
+func checkJoinTx(ctx context.Context, opts *TxOptions) error {
+	if opts == nil {
+		return nil
+	}
+	outer, _ := ctx.Value(txOptionsKey{}).(sql.TxOptions)
+	if opts.Isolation != sql.LevelDefault && opts.Isolation != outer.Isolation {
+		return fmt.Errorf("%w: isolation %s, active transaction uses %s", ErrTxOptionsConflict, opts.Isolation, outer.Isolation)
+	}
+	if outer.ReadOnly && !opts.ReadOnly {
+		return fmt.Errorf("%w: read-write requested inside a read-only transaction", ErrTxOptionsConflict)
+	}
+	return nil
+}
+
+// backoff 计算第 attempt 次重试前的等待时间（指数退避 + 抖动）
+func (o *TxOptions) backoff(attempt int) time.Duration {
+	d := o.BaseBackoff
+	for i := 1; i < attempt && d < o.MaxBackoff; i++ {
+		d *= 2
+	}
+	if o.MaxBackoff > 0 && d > o.MaxBackoff {
+		d = o.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// 在 [d/2, d) 区间内随机抖动，避免并发事务同时重试再次冲突
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// TxMetrics 事务重试指标接口
+type TxMetrics interface {
+	// OnRetry 事务因可重试错误即将进行第 attempt 次尝试
+	OnRetry(attempt int, err error)
+
+	// OnExhausted 达到最大尝试次数后仍失败
+	OnExhausted(attempts int, err error)
+}
+
+// TxStats 基于原子计数器的事务重试指标
+type TxStats struct {
+	retries   atomic.Int64
+	exhausted atomic.Int64
+}
+
+// DefaultTxStats 默认事务重试指标
+var DefaultTxStats = &TxStats{}
+
+// OnRetry 记录一次重试
+func (s *TxStats) OnRetry(attempt int, err error) {
+	s.retries.Add(1)
+}
+
+// OnExhausted 记录一次重试耗尽
+func (s *TxStats) OnExhausted(attempts int, err error) {
+	s.exhausted.Add(1)
+}
+
+// Retries 累计重试次数
+func (s *TxStats) Retries() int64 {
+	return s.retries.Load()
+}
+
+// Exhausted 累计重试耗尽次数
+func (s *TxStats) Exhausted() int64 {
+	return s.exhausted.Load()
+}
+
+// IsRetryableError 判断错误是否为可重试的并发冲突错误
+// 按数据库类型分别识别：
+//
+//	PostgreSQL: 40001 serialization_failure, 40P01 deadlock_detected
+//	MySQL:      1213 ER_LOCK_DEADLOCK, 1205 ER_LOCK_WAIT_TIMEOUT
+//	SQLite:     SQLITE_BUSY, SQLITE_LOCKED
+func IsRetryableError(dbType DatabaseType, err error) bool {
+	if err == nil {
+		return false
+	}
+	switch dbType {
+	case PostgreSQL:
+		if pgErr, ok := asPgError(err); ok {
+			return pgErr.Code == "40001" || pgErr.Code == "40P01"
+		}
+	case MySQL:
+		if myErr, ok := asMySQLError(err); ok {
+			return myErr.Number == 1213 || myErr.Number == 1205
+		}
+	case SQLite:
+		if liteErr, ok := asSQLiteError(err); ok {
+			return liteErr.Code == sqlite3.ErrBusy || liteErr.Code == sqlite3.ErrLocked
+		}
+	}
+	return false
+}
+
+// sleepContext 等待指定时间，上下文取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
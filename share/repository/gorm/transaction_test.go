@@ -0,0 +1,163 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// countingMetrics 记录重试回调次数
+type countingMetrics struct {
+	retries   []int
+	exhausted int
+}
+
+func (m *countingMetrics) OnRetry(attempt int, err error)      { m.retries = append(m.retries, attempt) }
+func (m *countingMetrics) OnExhausted(attempts int, err error) { m.exhausted = attempts }
+
+func TestWithTxOptionsRetry(t *testing.T) {
+	busy := sqlite3.Error{Code: sqlite3.ErrBusy}
+	tests := []struct {
+		name          string
+		maxAttempts   int
+		failures      int   // 前 failures 次尝试返回 err
+		err           error // 尝试返回的错误
+		wantCalls     int
+		wantRetries   int
+		wantExhausted int
+		wantErr       bool
+	}{
+		{name: "success", maxAttempts: 3, wantCalls: 1},
+		{name: "retry then success", maxAttempts: 3, failures: 2, err: busy, wantCalls: 3, wantRetries: 2},
+		{name: "exhausted", maxAttempts: 3, failures: 5, err: busy, wantCalls: 3, wantRetries: 2, wantExhausted: 3, wantErr: true},
+		{name: "not retryable", maxAttempts: 3, failures: 5, err: errors.New("boom"), wantCalls: 1, wantErr: true},
+		{name: "retry disabled", maxAttempts: 1, failures: 5, err: busy, wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewGormRepository[autoEntity, int](openTestDB(t, &autoEntity{}))
+			metrics := &countingMetrics{}
+			opts := &TxOptions{MaxAttempts: tt.maxAttempts, Metrics: metrics}
+
+			calls := 0
+			err := repo.WithTxOptions(context.Background(), opts, func(ctx context.Context) error {
+				calls++
+				if err := repo.Create(ctx, &autoEntity{Name: "a"}); err != nil {
+					return err
+				}
+				if calls <= tt.failures {
+					return tt.err
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls || len(metrics.retries) != tt.wantRetries || metrics.exhausted != tt.wantExhausted {
+				t.Errorf("calls=%d retries=%v exhausted=%d", calls, metrics.retries, metrics.exhausted)
+			}
+
+			// 失败的尝试均已回滚，只保留成功的一次写入
+			rows, _ := repo.List(context.Background())
+			want := 1
+			if tt.wantErr {
+				want = 0
+			}
+			if len(rows) != want {
+				t.Errorf("rows = %d, want %d", len(rows), want)
+			}
+		})
+	}
+}
+
+func TestWithTxOptionsJoin(t *testing.T) {
+	tests := []struct {
+		name    string
+		outer   *sql.TxOptions
+		inner   *TxOptions
+		wantErr bool
+	}{
+		{name: "default joins", inner: DefaultTxOptions()},
+		{name: "nil options join", inner: nil},
+		{name: "same isolation", outer: &sql.TxOptions{Isolation: sql.LevelSerializable}, inner: &TxOptions{Isolation: sql.LevelSerializable}},
+		{name: "read-only inside read-write", inner: &TxOptions{ReadOnly: true}},
+		{name: "isolation conflict", outer: &sql.TxOptions{Isolation: sql.LevelReadCommitted}, inner: &TxOptions{Isolation: sql.LevelSerializable}, wantErr: true},
+		{name: "isolation inside default", inner: &TxOptions{Isolation: sql.LevelSerializable}, wantErr: true},
+		{name: "read-write inside read-only", outer: &sql.TxOptions{ReadOnly: true}, inner: DefaultTxOptions(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, &autoEntity{})
+			repo := NewGormRepository[autoEntity, int](db)
+			// SQLite 不支持指定隔离级别，只校验上下文中记录的外层事务选项
+			ctx := context.WithValue(context.Background(), txKey{}, db.Begin())
+			if tt.outer != nil {
+				ctx = context.WithValue(ctx, txOptionsKey{}, *tt.outer)
+			}
+			defer func() { _ = repo.Rollback(ctx) }()
+
+			called := false
+			err := repo.WithTxOptions(ctx, tt.inner, func(context.Context) error {
+				called = true
+				return nil
+			})
+			if tt.wantErr {
+				if !errors.Is(err, ErrTxOptionsConflict) || called {
+					t.Fatalf("err = %v, called = %v, want ErrTxOptionsConflict", err, called)
+				}
+				return
+			}
+			if err != nil || !called {
+				t.Fatalf("err = %v, called = %v", err, called)
+			}
+		})
+	}
+}
+
+func TestWithTxJoinsOuterTransaction(t *testing.T) {
+	repo := NewGormRepository[autoEntity, int](openTestDB(t, &autoEntity{}))
+	ctx := context.Background()
+
+	err := repo.WithTx(ctx, func(ctx context.Context) error {
+		if err := repo.Create(ctx, &autoEntity{Name: "outer"}); err != nil {
+			return err
+		}
+		if err := repo.WithTx(ctx, func(ctx context.Context) error {
+			return repo.Create(ctx, &autoEntity{Name: "inner"})
+		}); err != nil {
+			return err
+		}
+		return errors.New("rollback outer")
+	})
+	if err == nil {
+		t.Fatal("expected outer error")
+	}
+	// 内层事务加入外层事务，随外层一起回滚
+	if rows, _ := repo.List(ctx); len(rows) != 0 {
+		t.Errorf("rows = %d, want 0", len(rows))
+	}
+}
+
+func TestTxOptionsBackoff(t *testing.T) {
+	opts := &TxOptions{BaseBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{attempt: 1, min: 5 * time.Millisecond, max: 10 * time.Millisecond},
+		{attempt: 2, min: 10 * time.Millisecond, max: 20 * time.Millisecond},
+		{attempt: 3, min: 20 * time.Millisecond, max: 40 * time.Millisecond},
+		{attempt: 10, min: 25 * time.Millisecond, max: 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := opts.backoff(tt.attempt); d < tt.min || d > tt.max {
+				t.Fatalf("backoff(%d) = %v, want [%v, %v]", tt.attempt, d, tt.min, tt.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package gorm
+
+import (
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"soliton-client/share/validation"
+)
+
+// RegisterValidationCallbacks 注册实体校验回调到 GORM
+// 在 Create、Update、Save 以及 Upsert 写入数据库前执行 validate 标签校验与 Validatable.Validate，
+// 校验失败时中止写入并返回带字段错误明细的 AppError
+func RegisterValidationCallbacks(db *gorm.DB) {
+	db.Callback().Create().Before("gorm:create").After("gorm:before_create").
+		Register("validation:before_create", validateModels)
+	db.Callback().Update().Before("gorm:update").After("gorm:before_update").
+		Register("validation:before_update", func(tx *gorm.DB) {
+			if updates, ok := tx.Statement.Dest.(map[string]interface{}); ok {
+				validateUpdates(tx, updates)
+				return
+			}
+			validateModels(tx)
+		})
+}
+
+// validateModels 校验语句中的每个实体
+func validateModels(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return
+	}
+	forEachModel(tx.Statement.ReflectValue, func(rv reflect.Value) {
+		if tx.Error != nil {
+			return
+		}
+		if rv.Kind() != reflect.Ptr {
+			if !rv.CanAddr() {
+				return
+			}
+			rv = rv.Addr()
+		}
+		if err := validation.Struct(tx.Statement.Context, rv.Interface()); err != nil {
+			_ = tx.AddError(err)
+		}
+	})
+}
+
+// validateUpdates 校验按字段更新（Updates(map)、UpdateFields）涉及的字段
+// 将待更新的值写入实体副本后只校验这些字段的 validate 标签；
+// 实体实现 Validatable 时，再读取将被更新的记录，合并待更新的值后调用 Validate，保证跨字段规则同样生效
+func validateUpdates(tx *gorm.DB, updates map[string]interface{}) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return
+	}
+
+	ctx := tx.Statement.Context
+	model := reflect.New(tx.Statement.Schema.ModelType)
+	fields := make([]string, 0, len(updates))
+	values := make(map[*schema.Field]interface{}, len(updates))
+	for key, value := range updates {
+		field := tx.Statement.Schema.LookUpField(key)
+		if field == nil {
+			continue
+		}
+		// 表达式（如 version + 1）无法在写入前求值，跳过
+		if _, isExpr := value.(clause.Expression); isExpr {
+			continue
+		}
+		if err := field.Set(ctx, model.Elem(), value); err != nil {
+			continue
+		}
+		fields = append(fields, strings.Join(field.BindNames, "."))
+		values[field] = value
+	}
+
+	if err := validation.StructPartial(ctx, model.Interface(), fields...); err != nil {
+		_ = tx.AddError(err)
+		return
+	}
+	if model.Type().Implements(validatableType) {
+		validateMerged(tx, values)
+	}
+}
+
+// validatableType validation.Validatable 接口类型
+var validatableType = reflect.TypeOf((*validation.Validatable)(nil)).Elem()
+
+// validateMerged 读取将被更新的记录，合并待更新的值后调用 Validatable.Validate
+// 使用语句的 WHERE 条件与模型上的主键定位记录，两者都没有时无法确定记录，跳过
+func validateMerged(tx *gorm.DB, values map[*schema.Field]interface{}) {
+	ctx := tx.Statement.Context
+	query := tx.Session(&gorm.Session{NewDB: true, SkipHooks: true})
+	located := false
+	if where, ok := tx.Statement.Clauses["WHERE"]; ok && where.Expression != nil {
+		query = query.Clauses(where.Expression)
+		located = true
+	}
+	if rv := reflect.Indirect(tx.Statement.ReflectValue); rv.Kind() == reflect.Struct {
+		for _, field := range tx.Statement.Schema.PrimaryFields {
+			if value, zero := field.ValueOf(ctx, rv); !zero {
+				query = query.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+				located = true
+			}
+		}
+	}
+	if !located {
+		return
+	}
+
+	rows := reflect.New(reflect.SliceOf(reflect.PointerTo(tx.Statement.Schema.ModelType)))
+	if err := query.Find(rows.Interface()).Error; err != nil {
+		_ = tx.AddError(err)
+		return
+	}
+	for i := 0; i < rows.Elem().Len(); i++ {
+		row := rows.Elem().Index(i)
+		for field, value := range values {
+			if err := field.Set(ctx, row.Elem(), value); err != nil {
+				_ = tx.AddError(err)
+				return
+			}
+		}
+		if err := validation.Custom(ctx, row.Interface()); err != nil {
+			_ = tx.AddError(err)
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// accountEntity 带校验标签的实体
+type accountEntity struct {
+	BaseEntity
+	Email string `json:"email" validate:"required,email"`
+	Level int    `json:"level" validate:"gte=0,lte=9"`
+}
+
+func TestValidationCallbacks(t *testing.T) {
+	tests := []struct {
+		name    string
+		write   func(db *gorm.DB, existing *accountEntity) error
+		wantErr bool
+	}{
+		{name: "create valid", write: func(db *gorm.DB, _ *accountEntity) error {
+			return db.Create(&accountEntity{Email: "b@example.com"}).Error
+		}},
+		{name: "create invalid", wantErr: true, write: func(db *gorm.DB, _ *accountEntity) error {
+			return db.Create(&accountEntity{Email: "bad"}).Error
+		}},
+		{name: "batch create with one invalid", wantErr: true, write: func(db *gorm.DB, _ *accountEntity) error {
+			return db.Create([]*accountEntity{{Email: "c@example.com"}, {Email: ""}}).Error
+		}},
+		{name: "save invalid", wantErr: true, write: func(db *gorm.DB, e *accountEntity) error {
+			e.Level = 10
+			return db.Save(e).Error
+		}},
+		{name: "updates map checks listed fields only", write: func(db *gorm.DB, e *accountEntity) error {
+			// Email 不在更新字段中，实体副本上为空也不报错
+			return db.Model(e).Updates(map[string]interface{}{"level": 3}).Error
+		}},
+		{name: "updates map invalid", wantErr: true, write: func(db *gorm.DB, e *accountEntity) error {
+			return db.Model(e).Updates(map[string]interface{}{"email": "bad"}).Error
+		}},
+		{name: "updates map expression skipped", write: func(db *gorm.DB, e *accountEntity) error {
+			return db.Model(e).Updates(map[string]interface{}{"level": gorm.Expr("level + 100")}).Error
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, &accountEntity{})
+			RegisterValidationCallbacks(db)
+			existing := &accountEntity{Email: "a@example.com", Level: 1}
+			if err := db.Create(existing).Error; err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			err := tt.write(db.WithContext(context.Background()), existing)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+			if appErr, ok := apperrors.AsAppError(err); !ok || appErr.Code != apperrors.Validation || len(appErr.Violations) == 0 {
+				t.Fatalf("err = %v, want validation error with violations", err)
+			}
+			// 校验失败时不写入任何数据
+			var stored []accountEntity
+			db.Find(&stored)
+			if len(stored) != 1 || stored[0].Email != existing.Email || stored[0].Level != 1 {
+				t.Fatalf("stored = %+v", stored)
+			}
+		})
+	}
+}
+
+// rangeEntity 带跨字段校验的实体
+type rangeEntity struct {
+	BaseEntity
+	Min int `json:"min" validate:"gte=0"`
+	Max int `json:"max"`
+}
+
+// Validate 最小值不能大于最大值
+func (e *rangeEntity) Validate(context.Context) error {
+	if e.Min > e.Max {
+		return errors.New("min 不能大于 max")
+	}
+	return nil
+}
+
+func TestValidationCallbacksMergedModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		write   func(ctx context.Context, repo *GormRepository[rangeEntity, int], e *rangeEntity) error
+		wantErr bool
+	}{
+		{name: "updates map valid", write: func(ctx context.Context, repo *GormRepository[rangeEntity, int], e *rangeEntity) error {
+			return repo.DB().Model(e).Updates(map[string]interface{}{"min": 3}).Error
+		}},
+		{name: "updates map breaks cross-field rule", wantErr: true, write: func(ctx context.Context, repo *GormRepository[rangeEntity, int], e *rangeEntity) error {
+			// 只更新 min，与数据库中的 max 合并后校验
+			return repo.DB().Model(e).Updates(map[string]interface{}{"min": 10}).Error
+		}},
+		{name: "update fields breaks cross-field rule", wantErr: true, write: func(ctx context.Context, repo *GormRepository[rangeEntity, int], e *rangeEntity) error {
+			return repo.UpdateFields(ctx, e.ID, map[string]interface{}{"Max": 0})
+		}},
+		{name: "update fields by where", wantErr: true, write: func(ctx context.Context, repo *GormRepository[rangeEntity, int], e *rangeEntity) error {
+			return repo.DB().Model(&rangeEntity{}).Where("id = ?", e.ID).Updates(map[string]interface{}{"max": 0}).Error
+		}},
+		{name: "tag still checked first", wantErr: true, write: func(ctx context.Context, repo *GormRepository[rangeEntity, int], e *rangeEntity) error {
+			return repo.UpdateFields(ctx, e.ID, map[string]interface{}{"Min": -1})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, &rangeEntity{})
+			RegisterValidationCallbacks(db)
+			repo := NewGormRepository[rangeEntity, int](db)
+			ctx := context.Background()
+			existing := &rangeEntity{Min: 1, Max: 5}
+			if err := repo.Create(ctx, existing); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			err := tt.write(ctx, repo, existing)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+			if appErr, ok := apperrors.AsAppError(err); !ok || appErr.Code != apperrors.Validation {
+				t.Fatalf("err = %v, want validation error", err)
+			}
+			stored, err := repo.GetByID(ctx, existing.ID)
+			if err != nil || stored.Min != 1 || stored.Max != 5 {
+				t.Fatalf("stored = %+v, %v", stored, err)
+			}
+		})
+	}
+}
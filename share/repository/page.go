@@ -6,8 +6,30 @@ type PageRequest struct {
 	Size       int          `json:"size"`       // 每页数量
 	Conditions []*Condition `json:"conditions"` // 查询条件列表
 	OrderBy    []OrderBy    `json:"order_by"`   // 排序规则
+	CountMode  CountMode    `json:"count_mode"` // 总数统计方式
 }
 
+// CountMode 总数统计方式
+type CountMode string
+
+const (
+	CountExact     CountMode = "exact"     // 执行 COUNT(*) 精确统计
+	CountNone      CountMode = "none"      // 不统计总数，多查询一条判断是否有下一页
+	CountEstimated CountMode = "estimated" // 使用 PostgreSQL 查询计划的估算行数（其他数据库按精确统计）
+)
+
+// TotalKind 分页结果中总数的类型
+type TotalKind string
+
+const (
+	TotalExact     TotalKind = "exact"     // 精确值
+	TotalEstimated TotalKind = "estimated" // 估算值
+	TotalUnknown   TotalKind = "unknown"   // 未统计
+)
+
+// RelevanceField 按全文检索相关度排序的特殊字段名
+const RelevanceField = "_relevance"
+
 // OrderBy 排序规则
 type OrderBy struct {
 	Field string `json:"field"` // 排序字段
@@ -27,6 +49,7 @@ func NewPageRequest(page, size int) *PageRequest {
 		Size:       size,
 		Conditions: make([]*Condition, 0),
 		OrderBy:    make([]OrderBy, 0),
+		CountMode:  CountExact,
 	}
 }
 
@@ -42,6 +65,17 @@ func (p *PageRequest) WithOrderBy(field string, desc bool) *PageRequest {
 	return p
 }
 
+// WithRelevanceOrder 按全文检索相关度降序排序
+func (p *PageRequest) WithRelevanceOrder() *PageRequest {
+	return p.WithOrderBy(RelevanceField, true)
+}
+
+// WithCountMode 设置总数统计方式
+func (p *PageRequest) WithCountMode(mode CountMode) *PageRequest {
+	p.CountMode = mode
+	return p
+}
+
 // Offset 计算偏移量
 func (p *PageRequest) Offset() int {
 	return (p.Page - 1) * p.Size
@@ -54,10 +88,44 @@ type PageResult[T any] struct {
 	Page       int   `json:"page"`        // 当前页码
 	Size       int   `json:"size"`        // 每页数量
 	TotalPages int   `json:"total_pages"` // 总页数
+
+	TotalKind TotalKind `json:"total_kind"` // 总数类型（精确、估算或未统计）
+	HasMore   bool      `json:"has_more"`   // 是否有下一页
+
+	Scores []float64 `json:"scores,omitempty"` // 与 Items 一一对应的相关度得分（仅全文检索时返回）
 }
 
-// NewPageResult 创建分页结果
+// NewPageResult 创建分页结果（精确总数）
 func NewPageResult[T any](items []T, total int64, page, size int) *PageResult[T] {
+	result := newPageResult(items, total, page, size, TotalExact)
+	result.HasMore = page < result.TotalPages
+	return result
+}
+
+// NewEstimatedPageResult 创建总数为估算值的分页结果
+// 估算值小于已确定存在的记录数时按已确定的记录数修正
+func NewEstimatedPageResult[T any](items []T, estimate int64, page, size int, hasMore bool) *PageResult[T] {
+	seen := int64((page-1)*size + len(items))
+	if hasMore {
+		seen++
+	}
+	if estimate < seen {
+		estimate = seen
+	}
+	result := newPageResult(items, estimate, page, size, TotalEstimated)
+	result.HasMore = hasMore
+	return result
+}
+
+// NewUncountedPageResult 创建未统计总数的分页结果
+func NewUncountedPageResult[T any](items []T, page, size int, hasMore bool) *PageResult[T] {
+	result := newPageResult(items, 0, page, size, TotalUnknown)
+	result.HasMore = hasMore
+	return result
+}
+
+// newPageResult 创建分页结果
+func newPageResult[T any](items []T, total int64, page, size int, kind TotalKind) *PageResult[T] {
 	totalPages := int(total) / size
 	if int(total)%size != 0 {
 		totalPages++
@@ -68,12 +136,13 @@ func NewPageResult[T any](items []T, total int64, page, size int) *PageResult[T]
 		Page:       page,
 		Size:       size,
 		TotalPages: totalPages,
+		TotalKind:  kind,
 	}
 }
 
 // HasNext 是否有下一页
 func (p *PageResult[T]) HasNext() bool {
-	return p.Page < p.TotalPages
+	return p.HasMore
 }
 
 // HasPrev 是否有上一页
@@ -0,0 +1,30 @@
+package repository
+
+import "testing"
+
+func TestNewPageResults(t *testing.T) {
+	items := []int{1, 2}
+	tests := []struct {
+		name      string
+		result    *PageResult[int]
+		wantTotal int64
+		wantPages int
+		wantMore  bool
+	}{
+		{name: "exact", result: NewPageResult(items, 5, 1, 2), wantTotal: 5, wantPages: 3, wantMore: true},
+		{name: "exact last page", result: NewPageResult(items, 4, 2, 2), wantTotal: 4, wantPages: 2},
+		{name: "estimate kept", result: NewEstimatedPageResult(items, 100, 1, 2, true), wantTotal: 100, wantPages: 50, wantMore: true},
+		// 估算值偏小时按已确定存在的记录数修正：前两页 4 条加上下一页至少 1 条
+		{name: "estimate raised", result: NewEstimatedPageResult(items, 1, 2, 2, true), wantTotal: 5, wantPages: 3, wantMore: true},
+		{name: "estimate raised last page", result: NewEstimatedPageResult(items, 0, 2, 2, false), wantTotal: 4, wantPages: 2},
+		{name: "uncounted", result: NewUncountedPageResult(items, 3, 2, true), wantTotal: 0, wantPages: 0, wantMore: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.result
+			if r.Total != tt.wantTotal || r.TotalPages != tt.wantPages || r.HasNext() != tt.wantMore {
+				t.Errorf("result = total %d pages %d more %v", r.Total, r.TotalPages, r.HasNext())
+			}
+		})
+	}
+}
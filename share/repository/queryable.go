@@ -1,6 +1,9 @@
 package repository
 
-import "context"
+import (
+	"context"
+	"strings"
+)
 
 // Operator 操作符类型
 type Operator string
@@ -15,7 +18,9 @@ const (
 	OpLessOrEqual    Operator = "<="
 
 	// 模糊匹配
-	OpLike Operator = "LIKE"
+	OpLike    Operator = "LIKE"
+	OpNotLike Operator = "NOT LIKE"
+	OpILike   Operator = "ILIKE"
 
 	// 集合操作
 	OpIn    Operator = "IN"
@@ -27,6 +32,18 @@ const (
 	// 空值检查
 	OpIsNull    Operator = "IS NULL"
 	OpIsNotNull Operator = "IS NOT NULL"
+
+	// JSON 操作（PostgreSQL JSONB）
+	OpJSONPathEqual Operator = "JSON PATH ="
+	OpJSONContains  Operator = "JSON @>"
+	OpJSONHasKey    Operator = "JSON ?"
+
+	// 数组操作（PostgreSQL 数组）
+	OpArrayContains Operator = "ARRAY @>"
+	OpArrayOverlaps Operator = "ARRAY &&"
+
+	// 全文检索
+	OpFullText Operator = "FULL TEXT"
 )
 
 // Condition 查询条件
@@ -75,11 +92,55 @@ func Lte(field string, value interface{}) *Condition {
 	return NewCondition(field, OpLessOrEqual, value)
 }
 
-// Like 模糊匹配条件
+// Like 模糊匹配条件，value 为 LIKE 模式（% 与 _ 为通配符，\ 为转义符）
 func Like(field string, value string) *Condition {
 	return NewCondition(field, OpLike, value)
 }
 
+// NotLike 模糊不匹配条件，value 为 LIKE 模式
+func NotLike(field string, value string) *Condition {
+	return NewCondition(field, OpNotLike, value)
+}
+
+// LikeLiteral 包含子串条件，value 按字面值匹配（% 与 _ 不作为通配符），是否区分大小写取决于数据库
+func LikeLiteral(field string, value string) *Condition {
+	return Like(field, "%"+EscapeLike(value)+"%")
+}
+
+// NotLikeLiteral 不包含子串条件，value 按字面值匹配
+func NotLikeLiteral(field string, value string) *Condition {
+	return NotLike(field, "%"+EscapeLike(value)+"%")
+}
+
+// ILike 不区分大小写的模糊匹配条件，value 为 LIKE 模式（\ 为转义符）
+// PostgreSQL 使用 ILIKE，其他数据库使用 LOWER(field) LIKE LOWER(value)
+func ILike(field string, value string) *Condition {
+	return NewCondition(field, OpILike, value)
+}
+
+// Contains 包含子串条件（不区分大小写，value 按字面值匹配）
+func Contains(field string, value string) *Condition {
+	return ILike(field, "%"+EscapeLike(value)+"%")
+}
+
+// StartsWith 前缀匹配条件（不区分大小写，value 按字面值匹配）
+func StartsWith(field string, value string) *Condition {
+	return ILike(field, EscapeLike(value)+"%")
+}
+
+// EndsWith 后缀匹配条件（不区分大小写，value 按字面值匹配）
+func EndsWith(field string, value string) *Condition {
+	return ILike(field, "%"+EscapeLike(value))
+}
+
+// likeEscaper LIKE 模式转义器
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike 转义 LIKE 通配符（% 与 _）及转义符 \，使 value 按字面值匹配
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
 // In 包含条件
 func In(field string, values interface{}) *Condition {
 	return NewCondition(field, OpIn, values)
@@ -105,6 +166,57 @@ func IsNotNull(field string) *Condition {
 	return NewCondition(field, OpIsNotNull, nil)
 }
 
+// JSONPath JSON 路径取值条件的值
+type JSONPath struct {
+	Path  []string    // 路径，如 ["address", "city"]
+	Value interface{} // 期望值（按文本比较）
+}
+
+// JSONPathEq JSON 路径取值等于条件，path 以 . 分隔，如 address.city
+// value 为 nil 时匹配值为 JSON null 或路径不存在的记录
+func JSONPathEq(field string, path string, value interface{}) *Condition {
+	return NewCondition(field, OpJSONPathEqual, JSONPath{Path: strings.Split(path, "."), Value: value})
+}
+
+// JSONContains JSON 包含条件（@>），value 会序列化为 JSON
+func JSONContains(field string, value interface{}) *Condition {
+	return NewCondition(field, OpJSONContains, value)
+}
+
+// JSONHasKey JSON 顶层键存在条件
+func JSONHasKey(field string, key string) *Condition {
+	return NewCondition(field, OpJSONHasKey, key)
+}
+
+// ArrayContains 数组包含全部元素条件（@>）
+func ArrayContains(field string, values interface{}) *Condition {
+	return NewCondition(field, OpArrayContains, values)
+}
+
+// ArrayOverlaps 数组包含任一元素条件（&&）
+func ArrayOverlaps(field string, values interface{}) *Condition {
+	return NewCondition(field, OpArrayOverlaps, values)
+}
+
+// FullTextQuery 全文检索条件的值
+type FullTextQuery struct {
+	Query    string   // 检索词，PostgreSQL 下支持 websearch 语法（"短语"、-排除、or）
+	Fields   []string // 参与检索的字段
+	Vector   string   // 预生成的 tsvector 列，为空时按 Fields 即时计算（仅 PostgreSQL）
+	Language string   // 文本检索配置，默认 simple（仅 PostgreSQL）
+}
+
+// FullText 全文检索条件
+// PostgreSQL 使用 tsvector/tsquery，MySQL 与 SQLite 回退为按词 LIKE 匹配
+func FullText(query string, fields ...string) *Condition {
+	return NewCondition(strings.Join(fields, ","), OpFullText, FullTextQuery{Query: query, Fields: fields})
+}
+
+// FullTextVector 使用预生成 tsvector 列的全文检索条件，fields 用于非 PostgreSQL 数据库的回退匹配
+func FullTextVector(vector string, query string, fields ...string) *Condition {
+	return NewCondition(vector, OpFullText, FullTextQuery{Query: query, Fields: fields, Vector: vector})
+}
+
 // QueryableRepository 可查询仓储接口，提供条件查询能力
 type QueryableRepository[T any, ID comparable] interface {
 	BaseRepository[T, ID]
@@ -0,0 +1,19 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Middleware 链路中间件
+// 接受或生成请求 ID 与 traceparent，保存到上下文并写入响应头；
+// 应注册在其他中间件之前，使错误处理、日志与下游调用都能获取链路信息
+func Middleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		info := New(string(c.GetHeader(HeaderRequestID)), string(c.GetHeader(HeaderTraceparent)))
+		c.Header(HeaderRequestID, info.RequestID)
+		c.Header(HeaderTraceparent, info.Traceparent())
+		c.Next(NewContext(ctx, info))
+	}
+}
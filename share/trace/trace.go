@@ -0,0 +1,144 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// 链路相关的请求头
+const (
+	HeaderRequestID   = "X-Request-Id" // 请求 ID
+	HeaderTraceparent = "traceparent"  // W3C Trace Context
+)
+
+// maxRequestIDLength 接受的请求 ID 最大长度，超过时重新生成
+const maxRequestIDLength = 128
+
+// Info 请求的链路信息
+type Info struct {
+	RequestID    string // 请求 ID
+	TraceID      string // 链路 ID（32 位十六进制）
+	SpanID       string // 当前服务的 span ID（16 位十六进制）
+	ParentSpanID string // 上游的 span ID，请求未携带 traceparent 时为空
+	Flags        string // trace-flags，如 01 表示已采样
+}
+
+// Traceparent 当前 span 的 traceparent 值
+func (i Info) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%s", i.TraceID, i.SpanID, i.Flags)
+}
+
+// infoKey 上下文中保存链路信息的键
+type infoKey struct{}
+
+// NewContext 将链路信息保存到上下文
+func NewContext(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext 从上下文获取链路信息
+func FromContext(ctx context.Context) (Info, bool) {
+	if ctx == nil {
+		return Info{}, false
+	}
+	info, ok := ctx.Value(infoKey{}).(Info)
+	return info, ok
+}
+
+// RequestID 上下文中的请求 ID，没有时返回空字符串
+func RequestID(ctx context.Context) string {
+	info, _ := FromContext(ctx)
+	return info.RequestID
+}
+
+// TraceID 上下文中的链路 ID，没有时返回空字符串
+func TraceID(ctx context.Context) string {
+	info, _ := FromContext(ctx)
+	return info.TraceID
+}
+
+// New 根据上游传入的请求 ID 与 traceparent 创建链路信息
+// 请求 ID 缺失或不合法时生成 UUID；traceparent 缺失或不合法时开启新的链路，当前服务总是使用新的 span ID
+func New(requestID, traceparent string) Info {
+	info := Info{RequestID: strings.TrimSpace(requestID), SpanID: randomHex(8), Flags: "01"}
+	if !validRequestID(info.RequestID) {
+		info.RequestID = uuid.NewString()
+	}
+	if traceID, parentID, flags, ok := ParseTraceparent(traceparent); ok {
+		info.TraceID, info.ParentSpanID, info.Flags = traceID, parentID, flags
+	} else {
+		info.TraceID = randomHex(16)
+	}
+	return info
+}
+
+// ParseTraceparent 解析 W3C traceparent（version-traceid-parentid-flags）
+func ParseTraceparent(value string) (traceID, parentID, flags string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || !isHex(parts[0], 2) || parts[0] == "ff" {
+		return "", "", "", false
+	}
+	// 版本 00 必须恰好四段，更高版本允许附加字段
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", "", "", false
+	}
+	traceID, parentID, flags = parts[1], parts[2], parts[3]
+	if !isHex(traceID, 32) || !isHex(parentID, 16) || !isHex(flags, 2) ||
+		strings.Trim(traceID, "0") == "" || strings.Trim(parentID, "0") == "" {
+		return "", "", "", false
+	}
+	return traceID, parentID, flags, true
+}
+
+// Inject 将上下文中的链路信息写入下游 HTTP 请求头
+// traceparent 使用同一链路 ID 与新的 span ID，表示对下游的一次调用
+func Inject(ctx context.Context, header http.Header) {
+	info, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	header.Set(HeaderRequestID, info.RequestID)
+	child := info
+	child.SpanID = randomHex(8)
+	header.Set(HeaderTraceparent, child.Traceparent())
+}
+
+// validRequestID 请求 ID 是否可接受（非空、长度受限、只含可见 ASCII 字符）
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// isHex 是否为指定长度的小写十六进制字符串
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// randomHex 生成 n 字节的随机十六进制字符串
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,140 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+const (
+	testTraceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentID = "00f067aa0ba902b7"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		wantOK bool
+	}{
+		{name: "valid", value: "00-" + testTraceID + "-" + testParentID + "-01", wantOK: true},
+		{name: "surrounding spaces", value: " 00-" + testTraceID + "-" + testParentID + "-00 ", wantOK: true},
+		{name: "future version extra fields", value: "01-" + testTraceID + "-" + testParentID + "-01-extra", wantOK: true},
+		{name: "version 00 extra fields", value: "00-" + testTraceID + "-" + testParentID + "-01-extra"},
+		{name: "forbidden version", value: "ff-" + testTraceID + "-" + testParentID + "-01"},
+		{name: "uppercase", value: "00-" + strings.ToUpper(testTraceID) + "-" + testParentID + "-01"},
+		{name: "zero trace id", value: "00-" + strings.Repeat("0", 32) + "-" + testParentID + "-01"},
+		{name: "zero parent id", value: "00-" + testTraceID + "-" + strings.Repeat("0", 16) + "-01"},
+		{name: "short trace id", value: "00-abc-" + testParentID + "-01"},
+		{name: "empty", value: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceID, parentID, _, ok := ParseTraceparent(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && (traceID != testTraceID || parentID != testParentID) {
+				t.Errorf("ids = %s %s", traceID, parentID)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestID     string
+		traceparent   string
+		wantRequestID string // 为空表示期望生成新的请求 ID
+		wantTraceID   string // 为空表示期望开启新的链路
+	}{
+		{name: "propagated", requestID: "req-1", traceparent: "00-" + testTraceID + "-" + testParentID + "-01", wantRequestID: "req-1", wantTraceID: testTraceID},
+		{name: "generated", requestID: "", traceparent: ""},
+		{name: "request id with spaces rejected", requestID: "a b", traceparent: "bad"},
+		{name: "request id too long", requestID: strings.Repeat("x", maxRequestIDLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := New(tt.requestID, tt.traceparent)
+			if tt.wantRequestID != "" && info.RequestID != tt.wantRequestID {
+				t.Errorf("request id = %q, want %q", info.RequestID, tt.wantRequestID)
+			}
+			if tt.wantRequestID == "" && (info.RequestID == "" || info.RequestID == tt.requestID) {
+				t.Errorf("request id = %q, want generated", info.RequestID)
+			}
+			if tt.wantTraceID != "" {
+				if info.TraceID != tt.wantTraceID || info.ParentSpanID != testParentID {
+					t.Errorf("trace = %+v, want trace %s parent %s", info, tt.wantTraceID, testParentID)
+				}
+			} else if !isHex(info.TraceID, 32) || info.ParentSpanID != "" {
+				t.Errorf("trace = %+v, want new trace", info)
+			}
+			// 当前服务总是使用新的 span ID，生成的 traceparent 可被再次解析
+			if info.SpanID == testParentID || !isHex(info.SpanID, 16) {
+				t.Errorf("span id = %q", info.SpanID)
+			}
+			if traceID, spanID, _, ok := ParseTraceparent(info.Traceparent()); !ok || traceID != info.TraceID || spanID != info.SpanID {
+				t.Errorf("traceparent = %q", info.Traceparent())
+			}
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	if RequestID(context.Background()) != "" || TraceID(context.Background()) != "" {
+		t.Fatal("empty context should have no trace info")
+	}
+	// nil 上下文不会 panic
+	if _, ok := FromContext(nil); ok {
+		t.Fatal("nil context should have no trace info")
+	}
+	info := New("req-1", "")
+	ctx := NewContext(context.Background(), info)
+	if RequestID(ctx) != "req-1" || TraceID(ctx) != info.TraceID {
+		t.Fatalf("request id = %q, trace id = %q", RequestID(ctx), TraceID(ctx))
+	}
+}
+
+func TestInject(t *testing.T) {
+	header := http.Header{}
+	Inject(context.Background(), header)
+	if len(header) != 0 {
+		t.Fatalf("header = %v, want empty without trace info", header)
+	}
+
+	info := New("req-1", "00-"+testTraceID+"-"+testParentID+"-01")
+	Inject(NewContext(context.Background(), info), header)
+	if header.Get(HeaderRequestID) != "req-1" {
+		t.Errorf("request id = %q", header.Get(HeaderRequestID))
+	}
+	// 下游调用沿用链路 ID，并使用新的 span ID
+	traceID, spanID, flags, ok := ParseTraceparent(header.Get(HeaderTraceparent))
+	if !ok || traceID != testTraceID || spanID == info.SpanID || flags != "01" {
+		t.Errorf("traceparent = %q", header.Get(HeaderTraceparent))
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got Info
+	c := app.NewContext(0)
+	c.Request.Header.Set(HeaderRequestID, "req-1")
+	c.Request.Header.Set(HeaderTraceparent, "00-"+testTraceID+"-"+testParentID+"-01")
+	c.SetHandlers(app.HandlersChain{Middleware(), func(ctx context.Context, c *app.RequestContext) {
+		got, _ = FromContext(ctx)
+	}})
+	c.Next(context.Background())
+
+	if got.RequestID != "req-1" || got.TraceID != testTraceID || got.ParentSpanID != testParentID {
+		t.Fatalf("info = %+v", got)
+	}
+	if id := string(c.Response.Header.Peek(HeaderRequestID)); id != "req-1" {
+		t.Errorf("response request id = %q", id)
+	}
+	if tp := string(c.Response.Header.Peek(HeaderTraceparent)); tp != got.Traceparent() {
+		t.Errorf("response traceparent = %q, want %q", tp, got.Traceparent())
+	}
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ResponseError 响应中的业务错误（错误码非 0）
+type ResponseError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Details json.RawMessage `json:"details,omitempty"`
+	TraceID string          `json:"trace_id,omitempty"`
+}
+
+// Error 实现 error 接口
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("[%d] %s", e.Code, e.Message)
+}
+
+// Decode 解析统一响应结构
+func Decode[T any](body []byte) (*Response[T], error) {
+	var resp Response[T]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &resp, nil
+}
+
+// DecodeReader 从 io.Reader 解析统一响应结构
+func DecodeReader[T any](r io.Reader) (*Response[T], error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return Decode[T](body)
+}
+
+// DecodeData 解析响应并返回业务数据，错误码非 0 时返回 *ResponseError
+func DecodeData[T any](body []byte) (T, error) {
+	var zero T
+	resp, err := Decode[json.RawMessage](body)
+	if err != nil {
+		return zero, err
+	}
+	if !resp.IsSuccess() {
+		respErr := &ResponseError{Code: resp.Code, Message: resp.Message, TraceID: resp.TraceID}
+		if resp.Details != nil {
+			respErr.Details, _ = json.Marshal(resp.Details)
+		}
+		return zero, respErr
+	}
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return zero, nil
+	}
+
+	var data T
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return zero, fmt.Errorf("decode response data: %w", err)
+	}
+	return data, nil
+}
+
+// legacyPageResult 旧版分页结果中与新版字段名不同的部分（list、page_size）
+type legacyPageResult[T any] struct {
+	List     []T `json:"list"`
+	PageSize int `json:"page_size"`
+}
+
+// DecodePage 解析分页响应并返回分页结果
+// 兼容旧版服务端返回的 list、page_size 字段，缺少的总页数与是否有下一页按总数推算
+func DecodePage[T any](body []byte) (*PageResult[T], error) {
+	raw, err := DecodeData[json.RawMessage](body)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+
+	var page PageResult[T]
+	if err := json.Unmarshal(raw, &page); err != nil {
+		return nil, fmt.Errorf("decode response data: %w", err)
+	}
+	var legacy legacyPageResult[T]
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return nil, fmt.Errorf("decode response data: %w", err)
+	}
+	if page.Items == nil && legacy.List != nil {
+		page.Items = legacy.List
+	}
+	if page.Size == 0 && legacy.PageSize > 0 {
+		page.Size = legacy.PageSize
+		if page.TotalPages == 0 {
+			page.TotalPages = int((page.Total + int64(page.Size) - 1) / int64(page.Size))
+			page.HasMore = page.Page < page.TotalPages
+		}
+	}
+	return &page, nil
+}
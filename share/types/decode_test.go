@@ -0,0 +1,100 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// userDTO 测试用业务数据
+type userDTO struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDecodeData(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		want     *userDTO
+		wantCode int // 期望的 ResponseError 错误码，0 表示不期望业务错误
+		wantErr  bool
+	}{
+		{name: "success", body: `{"code":0,"message":"success","data":{"id":1,"name":"alice"}}`, want: &userDTO{ID: 1, Name: "alice"}},
+		{name: "null data", body: `{"code":0,"message":"success","data":null}`},
+		{name: "missing data", body: `{"code":0,"message":"success"}`},
+		{
+			name:     "business error",
+			body:     `{"code":20002,"message":"用户不存在","details":{"id":1},"trace_id":"t1"}`,
+			wantCode: 20002,
+		},
+		{name: "invalid body", body: `<html>`, wantErr: true},
+		{name: "invalid data", body: `{"code":0,"data":{"id":"x"}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeData[*userDTO]([]byte(tt.body))
+			if tt.wantCode != 0 {
+				var respErr *ResponseError
+				if !errors.As(err, &respErr) || respErr.Code != tt.wantCode || respErr.TraceID != "t1" || string(respErr.Details) != `{"id":1}` {
+					t.Fatalf("err = %#v, want response error %d", err, tt.wantCode)
+				}
+				return
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("data = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePage(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *PageResult[userDTO]
+	}{
+		{
+			name: "current fields",
+			body: `{"code":0,"data":{"items":[{"id":1,"name":"a"}],"total":3,"page":1,"size":1,"total_pages":3,"total_kind":"exact","has_more":true}}`,
+			want: &PageResult[userDTO]{Items: []userDTO{{ID: 1, Name: "a"}}, Total: 3, Page: 1, Size: 1, TotalPages: 3, TotalKind: "exact", HasMore: true},
+		},
+		{
+			name: "legacy fields",
+			body: `{"code":0,"data":{"list":[{"id":2,"name":"b"}],"total":5,"page":2,"page_size":2}}`,
+			want: &PageResult[userDTO]{Items: []userDTO{{ID: 2, Name: "b"}}, Total: 5, Page: 2, Size: 2, TotalPages: 3, HasMore: true},
+		},
+		{
+			name: "legacy last page",
+			body: `{"code":0,"data":{"list":[],"total":4,"page":2,"page_size":2}}`,
+			want: &PageResult[userDTO]{Items: []userDTO{}, Total: 4, Page: 2, Size: 2, TotalPages: 2},
+		},
+		{name: "null data", body: `{"code":0,"data":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePage[userDTO]([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("page = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := DecodePage[userDTO]([]byte(`{"code":40001,"message":"参数错误"}`)); err == nil || !strings.Contains(err.Error(), "40001") {
+		t.Fatalf("err = %v, want response error", err)
+	}
+}
+
+func TestDecodeReader(t *testing.T) {
+	resp, err := DecodeReader[userDTO](strings.NewReader(`{"code":0,"message":"ok","data":{"id":3}}`))
+	if err != nil || !resp.IsSuccess() || resp.Data.ID != 3 {
+		t.Fatalf("resp = %+v, err = %v", resp, err)
+	}
+}
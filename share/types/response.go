@@ -1,16 +1,35 @@
 package types
 
-// Response 统一响应结构
-type Response struct {
+import (
+	"context"
+
+	"soliton-client/share/repository"
+	"soliton-client/share/trace"
+)
+
+// Response 统一响应结构，T 为业务数据类型
+type Response[T any] struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Data    T           `json:"data,omitempty"`
+	Details interface{} `json:"details,omitempty"` // 错误详情（字段校验错误等）
 	TraceID string      `json:"trace_id,omitempty"`
 }
 
+// IsSuccess 业务处理是否成功
+func (r *Response[T]) IsSuccess() bool {
+	return r.Code == 0
+}
+
+// WithTrace 填充上下文中的链路 ID
+func (r *Response[T]) WithTrace(ctx context.Context) *Response[T] {
+	r.TraceID = trace.TraceID(ctx)
+	return r
+}
+
 // Success 成功响应
-func Success(data interface{}) *Response {
-	return &Response{
+func Success[T any](data T) *Response[T] {
+	return &Response[T]{
 		Code:    0,
 		Message: "success",
 		Data:    data,
@@ -18,8 +37,8 @@ func Success(data interface{}) *Response {
 }
 
 // SuccessWithMessage 带消息的成功响应
-func SuccessWithMessage(message string, data interface{}) *Response {
-	return &Response{
+func SuccessWithMessage[T any](message string, data T) *Response[T] {
+	return &Response[T]{
 		Code:    0,
 		Message: message,
 		Data:    data,
@@ -27,17 +46,50 @@ func SuccessWithMessage(message string, data interface{}) *Response {
 }
 
 // Error 错误响应
-func Error(code int, message string) *Response {
-	return &Response{
+func Error(code int, message string) *Response[any] {
+	return &Response[any]{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// ErrorWithDetails 带详情的错误响应
+func ErrorWithDetails(code int, message string, details interface{}) *Response[any] {
+	return &Response[any]{
 		Code:    code,
 		Message: message,
+		Details: details,
 	}
 }
 
-// PageResult 分页结果
-type PageResult struct {
-	List     interface{} `json:"list"`
-	Total    int64       `json:"total"`
-	Page     int         `json:"page"`
-	PageSize int         `json:"page_size"`
+// PageResult 分页结果，与仓储层的分页结果使用同一结构（items、size 等字段）
+type PageResult[T any] = repository.PageResult[T]
+
+// PageResponse 分页响应
+type PageResponse[T any] = Response[*PageResult[T]]
+
+// SuccessPage 分页成功响应
+func SuccessPage[T any](page *PageResult[T]) *PageResponse[T] {
+	return Success(page)
+}
+
+// ConvertPage 转换分页结果的数据类型（如实体转换为 DTO），分页信息保持不变
+func ConvertPage[T, R any](page *repository.PageResult[T], convert func(T) R) *PageResult[R] {
+	if page == nil {
+		return nil
+	}
+	items := make([]R, len(page.Items))
+	for i, item := range page.Items {
+		items[i] = convert(item)
+	}
+	return &PageResult[R]{
+		Items:      items,
+		Total:      page.Total,
+		Page:       page.Page,
+		Size:       page.Size,
+		TotalPages: page.TotalPages,
+		TotalKind:  page.TotalKind,
+		HasMore:    page.HasMore,
+		Scores:     page.Scores,
+	}
 }
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"soliton-client/share/repository"
+)
+
+func TestConvertPage(t *testing.T) {
+	src := repository.NewPageResult([]int{1, 2}, 5, 1, 2)
+	got := ConvertPage(src, func(id int) userDTO { return userDTO{ID: id} })
+	if len(got.Items) != 2 || got.Items[1].ID != 2 {
+		t.Fatalf("items = %+v", got.Items)
+	}
+	if got.Total != 5 || got.Page != 1 || got.Size != 2 || got.TotalPages != 3 || !got.HasMore || got.TotalKind != src.TotalKind {
+		t.Errorf("page = %+v, want paging of %+v", got, src)
+	}
+	if ConvertPage[int, userDTO](nil, nil) != nil {
+		t.Error("nil page should convert to nil")
+	}
+}
+
+func TestSuccessPageRoundTrip(t *testing.T) {
+	page := ConvertPage(repository.NewPageResult([]int{7}, 1, 1, 10), func(id int) userDTO { return userDTO{ID: id} })
+	body, err := json.Marshal(SuccessPage(page))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(body), `"items":[{"id":7,"name":""}]`) {
+		t.Errorf("body = %s", body)
+	}
+	got, err := DecodePage[userDTO](body)
+	if err != nil || got.Items[0].ID != 7 || got.Size != 10 {
+		t.Fatalf("page = %+v, err = %v", got, err)
+	}
+}
@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// BindJSON 解析 JSON 请求体，规范化后校验
+// 解析与校验失败均返回带字段明细的 AppError，可直接交给 errors.HandleError
+func BindJSON(ctx context.Context, c *app.RequestContext, v interface{}) error {
+	if err := c.BindJSON(v); err != nil {
+		return Translate(err)
+	}
+	if normalizer, ok := v.(Normalizer); ok {
+		normalizer.Normalize()
+	}
+	return Struct(ctx, v)
+}
+
+// translateBinding 将请求体解析错误转换为 AppError
+func translateBinding(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return apperrors.Wrap(apperrors.BadRequest, "请求体格式错误，应为 JSON 对象", err)
+		}
+		violation := apperrors.FieldViolation{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("类型错误，应为%s", typeName(typeErr.Type)),
+		}
+		appErr := apperrors.Wrap(apperrors.Validation, fmt.Sprintf("%s %s", violation.Field, violation.Message), err)
+		return appErr.WithViolations(violation)
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return apperrors.Wrap(apperrors.BadRequest, "请求体不是合法的 JSON", err).
+			WithMetadata("offset", syntaxErr.Offset)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return apperrors.Wrap(apperrors.BadRequest, "请求体不是合法的 JSON", err)
+	}
+	return apperrors.Wrap(apperrors.BadRequest, "请求参数错误", err)
+}
+
+// typeName JSON 字段期望类型的描述
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "其他类型"
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "字符串"
+	case reflect.Bool:
+		return "布尔值"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "整数"
+	case reflect.Float32, reflect.Float64:
+		return "数字"
+	case reflect.Slice, reflect.Array:
+		return "数组"
+	case reflect.Map, reflect.Struct:
+		return "对象"
+	default:
+		return t.String()
+	}
+}
@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// newJSONContext 创建带 JSON 请求体的请求上下文
+func newJSONContext(body string) *app.RequestContext {
+	c := app.NewContext(0)
+	c.Request.Header.SetMethod("POST")
+	c.Request.Header.SetContentTypeBytes([]byte("application/json"))
+	c.Request.SetBodyString(body)
+	return c
+}
+
+// profile 测试用请求体
+type profile struct {
+	Name string   `json:"name" validate:"required"`
+	Age  int      `json:"age"`
+	Tags []string `json:"tags"`
+}
+
+func TestBindJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantCode     int    // 期望的错误码，0 表示成功
+		wantField    string // 期望的字段错误
+		wantRule     string
+		wantMetadata string // 期望存在的附加信息键
+	}{
+		{name: "valid", body: `{"name":"amy","age":3}`},
+		{name: "validation", body: `{"age":3}`, wantCode: apperrors.Validation, wantField: "name", wantRule: "required"},
+		{name: "field type", body: `{"name":"amy","age":"old"}`, wantCode: apperrors.Validation, wantField: "age", wantRule: "type"},
+		{name: "syntax", body: `{"name":1,}`, wantCode: apperrors.BadRequest, wantMetadata: "offset"},
+		{name: "not an object", body: `[1,2]`, wantCode: apperrors.BadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p profile
+			err := BindJSON(context.Background(), newJSONContext(tt.body), &p)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+			appErr, ok := apperrors.AsAppError(err)
+			if !ok || appErr.Code != tt.wantCode {
+				t.Fatalf("err = %v, want code %d", err, tt.wantCode)
+			}
+			if tt.wantMetadata != "" {
+				if _, ok := appErr.Metadata[tt.wantMetadata]; !ok {
+					t.Errorf("metadata = %v, want key %s", appErr.Metadata, tt.wantMetadata)
+				}
+			}
+			if tt.wantField == "" {
+				return
+			}
+			if len(appErr.Violations) != 1 || appErr.Violations[0].Field != tt.wantField || appErr.Violations[0].Rule != tt.wantRule {
+				t.Fatalf("violations = %+v, want %s/%s", appErr.Violations, tt.wantField, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	var p profile
+	err := BindJSON(context.Background(), newJSONContext(`{"name":"amy","tags":"go"}`), &p)
+	appErr, ok := apperrors.AsAppError(err)
+	if !ok || len(appErr.Violations) != 1 || appErr.Violations[0].Message != "类型错误，应为数组" {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+// Package validation 提供基于 validator/v10 的统一数据校验
+// 校验失败时返回带字段错误明细的 AppError（错误码 Validation）
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// Validatable 自定义校验接口
+// 实体或 DTO 实现此接口后，在结构体标签校验通过后调用
+type Validatable interface {
+	Validate(ctx context.Context) error
+}
+
+// Normalizer 数据规范化接口
+// DTO 实现此接口后，BindJSON 在解析请求体之后、校验之前调用（如去除首尾空格、邮箱转小写）
+type Normalizer interface {
+	Normalize()
+}
+
+// validate 全局校验器实例（validator 实例并发安全且会缓存结构体信息）
+var validate = newValidator()
+
+// newValidator 创建校验器，字段路径使用 JSON 字段名
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+// Validator 获取底层校验器，用于注册自定义规则
+func Validator() *validator.Validate {
+	return validate
+}
+
+// Struct 校验结构体：先执行 validate 标签校验，再调用 Validatable.Validate
+func Struct(ctx context.Context, v interface{}) error {
+	if err := validate.StructCtx(ctx, v); err != nil {
+		return Translate(err)
+	}
+	return custom(ctx, v)
+}
+
+// StructPartial 只校验指定字段（结构体字段名，嵌套字段用 . 分隔）
+// 用于部分更新场景，不调用 Validatable.Validate
+func StructPartial(ctx context.Context, v interface{}, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	if err := validate.StructPartialCtx(ctx, v, fields...); err != nil {
+		return Translate(err)
+	}
+	return nil
+}
+
+// Custom 只调用 Validatable.Validate，用于部分更新后对合并后的完整数据做自定义校验
+// 非 AppError 的错误转换为 Validation 错误
+func Custom(ctx context.Context, v interface{}) error {
+	return custom(ctx, v)
+}
+
+// custom 调用自定义校验
+func custom(ctx context.Context, v interface{}) error {
+	validatable, ok := v.(Validatable)
+	if !ok {
+		return nil
+	}
+	err := validatable.Validate(ctx)
+	if err == nil || apperrors.IsAppError(err) {
+		return err
+	}
+	return apperrors.ErrValidation(err.Error())
+}
+
+// Translate 将校验与请求体解析错误转换为带字段明细的 AppError
+// 支持 validator 校验错误与 JSON 解析错误（语法错误、字段类型错误），AppError 原样返回
+func Translate(err error) error {
+	if err == nil || apperrors.IsAppError(err) {
+		return err
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return translateValidation(validationErrors)
+	}
+	var invalid *validator.InvalidValidationError
+	if errors.As(err, &invalid) {
+		return fmt.Errorf("validation: %w", err)
+	}
+	return translateBinding(err)
+}
+
+// translateValidation 将 validator 错误转换为 AppError
+func translateValidation(validationErrors validator.ValidationErrors) error {
+	violations := make([]apperrors.FieldViolation, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		violations = append(violations, apperrors.FieldViolation{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+
+	appErr := apperrors.Wrap(apperrors.Validation, summary(violations), validationErrors)
+	return appErr.WithViolations(violations...)
+}
+
+// fieldPath 字段路径（去掉顶层结构体名），如 User.address.city -> address.city
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if idx := strings.Index(ns, "."); idx >= 0 {
+		return ns[idx+1:]
+	}
+	return fe.Field()
+}
+
+// summary 汇总错误信息
+func summary(violations []apperrors.FieldViolation) string {
+	if len(violations) == 1 {
+		return fmt.Sprintf("%s %s", violations[0].Field, violations[0].Message)
+	}
+	return fmt.Sprintf("参数校验失败，共 %d 个字段不合法", len(violations))
+}
+
+// message 生成单个规则的错误描述
+func message(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without", "required_without_all":
+		return "不能为空"
+	case "email":
+		return "邮箱格式不正确"
+	case "url", "uri", "http_url":
+		return "URL 格式不正确"
+	case "uuid", "uuid4", "uuid7":
+		return "UUID 格式不正确"
+	case "e164":
+		return "手机号格式不正确"
+	case "min":
+		if isLengthKind(fe.Kind()) {
+			return fmt.Sprintf("长度不能小于 %s", param)
+		}
+		return fmt.Sprintf("不能小于 %s", param)
+	case "max":
+		if isLengthKind(fe.Kind()) {
+			return fmt.Sprintf("长度不能大于 %s", param)
+		}
+		return fmt.Sprintf("不能大于 %s", param)
+	case "len":
+		return fmt.Sprintf("长度必须为 %s", param)
+	case "gt":
+		return fmt.Sprintf("必须大于 %s", param)
+	case "gte":
+		return fmt.Sprintf("必须大于等于 %s", param)
+	case "lt":
+		return fmt.Sprintf("必须小于 %s", param)
+	case "lte":
+		return fmt.Sprintf("必须小于等于 %s", param)
+	case "oneof":
+		return fmt.Sprintf("必须是以下值之一: %s", param)
+	case "alphanum":
+		return "只能包含字母和数字"
+	case "numeric", "number":
+		return "必须为数字"
+	case "eqfield":
+		return fmt.Sprintf("必须与 %s 一致", param)
+	default:
+		if param != "" {
+			return fmt.Sprintf("不满足校验规则 %s=%s", fe.Tag(), param)
+		}
+		return fmt.Sprintf("不满足校验规则 %s", fe.Tag())
+	}
+}
+
+// isLengthKind 是否按长度比较的类型
+func isLengthKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
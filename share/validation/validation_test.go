@@ -0,0 +1,179 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "soliton-client/share/errors"
+)
+
+// address 嵌套结构体
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+// signup 测试用请求
+type signup struct {
+	Email   string   `json:"email" validate:"required,email"`
+	Age     int      `json:"age" validate:"gte=18"`
+	Name    string   `json:"name" validate:"max=4"`
+	Address *address `json:"address" validate:"required"`
+	Invite  string   `json:"-" validate:"omitempty,len=6"`
+}
+
+// Validate 自定义校验：保留用户名不允许注册
+func (s *signup) Validate(context.Context) error {
+	if s.Name == "root" {
+		return errors.New("保留用户名")
+	}
+	if s.Name == "bot" {
+		return apperrors.ErrForbidden("禁止注册")
+	}
+	return nil
+}
+
+// violationFields 返回错误中的字段与规则
+func violationFields(t *testing.T, err error) map[string]string {
+	t.Helper()
+	appErr, ok := apperrors.AsAppError(err)
+	if !ok || appErr.Code != apperrors.Validation {
+		t.Fatalf("err = %v, want validation AppError", err)
+	}
+	fields := make(map[string]string, len(appErr.Violations))
+	for _, v := range appErr.Violations {
+		fields[v.Field] = v.Rule
+	}
+	return fields
+}
+
+func TestStruct(t *testing.T) {
+	valid := func() *signup {
+		return &signup{Email: "a@example.com", Age: 20, Name: "amy", Address: &address{City: "sh"}}
+	}
+	tests := []struct {
+		name       string
+		modify     func(s *signup)
+		wantFields map[string]string // 期望的字段错误，nil 表示无字段错误
+		wantCode   int               // 期望的错误码，0 表示校验通过
+	}{
+		{name: "valid", modify: func(*signup) {}},
+		{
+			name:       "json field names",
+			modify:     func(s *signup) { s.Email = "bad"; s.Age = 1 },
+			wantFields: map[string]string{"email": "email", "age": "gte"},
+			wantCode:   apperrors.Validation,
+		},
+		{
+			name:       "nested path",
+			modify:     func(s *signup) { s.Address.City = "" },
+			wantFields: map[string]string{"address.city": "required"},
+			wantCode:   apperrors.Validation,
+		},
+		{
+			name:       "ignored json name falls back to field name",
+			modify:     func(s *signup) { s.Invite = "abc" },
+			wantFields: map[string]string{"Invite": "len"},
+			wantCode:   apperrors.Validation,
+		},
+		{name: "custom plain error", modify: func(s *signup) { s.Name = "root" }, wantCode: apperrors.Validation},
+		{name: "custom app error kept", modify: func(s *signup) { s.Name = "bot" }, wantCode: apperrors.Forbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid()
+			tt.modify(s)
+			err := Struct(context.Background(), s)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+			if tt.wantFields != nil {
+				got := violationFields(t, err)
+				if len(got) != len(tt.wantFields) {
+					t.Fatalf("violations = %v, want %v", got, tt.wantFields)
+				}
+				for field, rule := range tt.wantFields {
+					if got[field] != rule {
+						t.Errorf("violation %s = %q, want %q", field, got[field], rule)
+					}
+				}
+				return
+			}
+			if appErr, ok := apperrors.AsAppError(err); !ok || appErr.Code != tt.wantCode {
+				t.Fatalf("err = %v, want code %d", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestStructPartial(t *testing.T) {
+	// 只填写了部分字段的实体
+	s := &signup{Email: "bad", Name: "root"}
+
+	tests := []struct {
+		name       string
+		fields     []string
+		wantFields map[string]string
+	}{
+		{name: "no fields", fields: nil},
+		{name: "valid field only", fields: []string{"Name"}},
+		{name: "invalid field", fields: []string{"Name", "Email"}, wantFields: map[string]string{"email": "email"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 部分校验不调用 Validatable.Validate，因此 root 不会触发自定义错误
+			err := StructPartial(context.Background(), s, tt.fields...)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+			got := violationFields(t, err)
+			for field, rule := range tt.wantFields {
+				if got[field] != rule {
+					t.Errorf("violation %s = %q, want %q", field, got[field], rule)
+				}
+			}
+		})
+	}
+}
+
+func TestViolationMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantMsg string
+	}{
+		{name: "string max", value: &struct {
+			V string `json:"v" validate:"max=2"`
+		}{V: "abc"}, wantMsg: "v 长度不能大于 2"},
+		{name: "number max", value: &struct {
+			V int `json:"v" validate:"max=2"`
+		}{V: 3}, wantMsg: "v 不能大于 2"},
+		{name: "oneof", value: &struct {
+			V string `json:"v" validate:"oneof=a b"`
+		}{V: "c"}, wantMsg: "v 必须是以下值之一: a b"},
+		{name: "unknown rule", value: &struct {
+			V string `json:"v" validate:"lowercase"`
+		}{V: "A"}, wantMsg: "v 不满足校验规则 lowercase"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(context.Background(), tt.value)
+			appErr, ok := apperrors.AsAppError(err)
+			if !ok || appErr.Message != tt.wantMsg {
+				t.Fatalf("err = %v, want message %q", err, tt.wantMsg)
+			}
+		})
+	}
+
+	multi := &signup{Email: "bad", Age: 1, Address: &address{City: "sh"}}
+	if appErr, _ := apperrors.AsAppError(Struct(context.Background(), multi)); appErr == nil ||
+		appErr.Message != "参数校验失败，共 2 个字段不合法" {
+		t.Fatalf("multi violation err = %v", appErr)
+	}
+}